@@ -0,0 +1,116 @@
+package bitbucket
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// avatarSchema returns the arguments uploading the avatar of a repository or
+// project from a file or base64 encoded content. The avatar is only uploaded
+// again when the SHA-256 of its content changes.
+func avatarSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"avatar_file": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ConflictsWith: []string{"avatar_base64", "link"},
+		},
+		"avatar_base64": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ValidateFunc:  validation.StringIsBase64,
+			ConflictsWith: []string{"avatar_file", "link"},
+		},
+		"avatar_sha256": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"avatar_url": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+// avatarGetter is implemented by both schema.ResourceData and
+// schema.ResourceDiff.
+type avatarGetter interface {
+	Get(key string) interface{}
+}
+
+// avatarContent returns the configured avatar, or nil when none is
+// configured.
+func avatarContent(d avatarGetter) ([]byte, error) {
+	if v, _ := d.Get("avatar_file").(string); v != "" {
+		content, err := os.ReadFile(v)
+		if err != nil {
+			return nil, fmt.Errorf("error reading avatar_file: %w", err)
+		}
+		return content, nil
+	}
+
+	if v, _ := d.Get("avatar_base64").(string); v != "" {
+		content, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding avatar_base64: %w", err)
+		}
+		return content, nil
+	}
+
+	return nil, nil
+}
+
+func avatarSHA256(content []byte) string {
+	if content == nil {
+		return ""
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// customizeAvatarDiff plans an upload when the content of the avatar differs
+// from the last uploaded one.
+func customizeAvatarDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("avatar_file") || !d.NewValueKnown("avatar_base64") {
+		return d.SetNewComputed("avatar_sha256")
+	}
+
+	content, err := avatarContent(d)
+	if err != nil {
+		return err
+	}
+
+	if hash := avatarSHA256(content); hash != d.Get("avatar_sha256").(string) {
+		return d.SetNew("avatar_sha256", hash)
+	}
+
+	return nil
+}
+
+// avatarUpload returns the avatar as a data URI when it has to be uploaded,
+// which Bitbucket accepts as the href of the avatar link.
+func avatarUpload(d *schema.ResourceData) (string, error) {
+	if !d.HasChange("avatar_sha256") {
+		return "", nil
+	}
+
+	content, err := avatarContent(d)
+	if err != nil || content == nil {
+		return "", err
+	}
+
+	return avatarDataURI(content), nil
+}
+
+func avatarDataURI(content []byte) string {
+	return fmt.Sprintf("data:%s;base64,%s", http.DetectContentType(content), base64.StdEncoding.EncodeToString(content))
+}
@@ -0,0 +1,119 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// testAvatarPNG is the signature of a PNG image, enough to be detected as one.
+var testAvatarPNG = []byte("\x89PNG\x0d\x0a\x1a\x0a")
+
+func testAvatarFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return path
+}
+
+// testAvatarImage writes a square PNG image of the given color, as Bitbucket
+// only accepts actual images as avatars.
+func testAvatarImage(t *testing.T, name string, c color.Color) string {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := 0; x < 64; x++ {
+		for y := 0; y < 64; y++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return testAvatarFile(t, name, buf.Bytes())
+}
+
+func TestCustomizeAvatarDiff(t *testing.T) {
+	hash := avatarSHA256(testAvatarPNG)
+	renamed := testAvatarFile(t, "renamed.png", testAvatarPNG)
+	changed := testAvatarFile(t, "changed.png", append(testAvatarPNG, 0))
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		expected string
+	}{
+		"unchanged content at another path": {
+			config:   map[string]interface{}{"avatar_file": renamed},
+			expected: "",
+		},
+		"unchanged base64 content": {
+			config:   map[string]interface{}{"avatar_base64": base64.StdEncoding.EncodeToString(testAvatarPNG)},
+			expected: "",
+		},
+		"changed content": {
+			config:   map[string]interface{}{"avatar_file": changed},
+			expected: avatarSHA256(append(testAvatarPNG, 0)),
+		},
+	}
+
+	for name, tc := range cases {
+		config := map[string]interface{}{
+			"owner":               "workspace",
+			"name":                "project",
+			"key":                 "PROJ",
+			"deletion_protection": false,
+		}
+		for k, v := range tc.config {
+			config[k] = v
+		}
+
+		state := &terraform.InstanceState{
+			ID: "workspace/PROJ",
+			Attributes: map[string]string{
+				"id":                  "workspace/PROJ",
+				"owner":               "workspace",
+				"name":                "project",
+				"key":                 "PROJ",
+				"is_private":          "true",
+				"deletion_protection": "false",
+				"on_destroy":          onDestroyDelete,
+				"avatar_sha256":       hash,
+			},
+		}
+
+		diff, err := resourceProject().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+
+		var planned string
+		if diff != nil && diff.Attributes["avatar_sha256"] != nil {
+			planned = diff.Attributes["avatar_sha256"].New
+		}
+
+		if planned != tc.expected {
+			t.Errorf("%s: expected avatar_sha256 to be planned as %q, got %q", name, tc.expected, planned)
+		}
+	}
+}
+
+func TestAvatarDataURI(t *testing.T) {
+	if got, expected := avatarDataURI(testAvatarPNG), "data:image/png;base64,iVBORw0KGgo="; got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
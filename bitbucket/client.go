@@ -144,3 +144,22 @@ func (c *Client) PutOnly(endpoint string) (*http.Response, error) {
 func (c *Client) Delete(endpoint string) (*http.Response, error) {
 	return c.Do("DELETE", endpoint, nil, "application/json")
 }
+
+// gitAuthorization returns the Authorization header for Git over HTTPS with
+// the credentials of the client, or an empty string without credentials.
+func (c *Client) gitAuthorization() (string, error) {
+	switch {
+	case c.Username != nil && c.Password != nil:
+		return basicAuthorization(*c.Username, *c.Password), nil
+	case c.OAuthToken != nil:
+		return basicAuthorization("x-token-auth", *c.OAuthToken), nil
+	case c.OAuthTokenSource != nil:
+		token, err := c.OAuthTokenSource.Token()
+		if err != nil {
+			return "", err
+		}
+		return basicAuthorization("x-token-auth", token.AccessToken), nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,139 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strollby/bitbucket-go-client"
+)
+
+func dataCommit() *schema.Resource {
+	dataSchema := commitSchema()
+	dataSchema["workspace"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	dataSchema["repo_slug"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+	}
+	dataSchema["revision"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Required:    true,
+		Description: "The branch, tag or commit hash to resolve",
+	}
+
+	return &schema.Resource{
+		ReadWithoutTimeout: dataReadCommit,
+		Schema:             dataSchema,
+	}
+}
+
+// commitSchema returns the attributes of a commit.
+func commitSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"hash": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"author": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"author_uuid": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"date": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"message": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"parents": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+func dataReadCommit(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	revision := d.Get("revision").(string)
+
+	commit, err := getCommit(&client, workspace, repoSlug, revision)
+	if err != nil {
+		return diag.Errorf("error reading Commit (%s/%s/%s): %s", workspace, repoSlug, revision, err)
+	}
+	if commit == nil {
+		return diag.Errorf("no branch, tag or commit %q in %s/%s", revision, workspace, repoSlug)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, commit.Hash))
+	for k, v := range flattenCommit(*commit) {
+		d.Set(k, v)
+	}
+
+	return nil
+}
+
+// getCommit returns the commit a branch, tag or commit points to, or nil when
+// there is none. Branch and tag names may contain slashes and other characters
+// that must be escaped in the path.
+func getCommit(client *Client, workspace, repoSlug, revision string) (*bitbucket.Commit, error) {
+	res, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/commit/%s",
+		url.PathEscape(workspace), url.PathEscape(repoSlug), url.PathEscape(revision)))
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var commit bitbucket.Commit
+	if err := json.NewDecoder(res.Body).Decode(&commit); err != nil {
+		return nil, err
+	}
+
+	return &commit, nil
+}
+
+func flattenCommit(commit bitbucket.Commit) map[string]interface{} {
+	parents := make([]interface{}, 0, len(commit.Parents))
+	for _, parent := range commit.Parents {
+		parents = append(parents, parent.Hash)
+	}
+
+	flat := map[string]interface{}{
+		"hash":    commit.Hash,
+		"message": strings.TrimSuffix(commit.Message, "\n"),
+		"parents": parents,
+	}
+
+	if commit.Author != nil {
+		flat["author"] = commit.Author.Raw
+		if commit.Author.User != nil {
+			flat["author_uuid"] = commit.Author.User.Uuid
+		}
+	}
+
+	if !commit.Date.IsZero() {
+		flat["date"] = commit.Date.Format(time.RFC3339)
+	}
+
+	return flat
+}
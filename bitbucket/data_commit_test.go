@@ -0,0 +1,117 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/strollby/bitbucket-go-client"
+)
+
+func TestAccDataSourceCommit_basic(t *testing.T) {
+	dataSourceName := "data.bitbucket_commit.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(dataSourceName, "hash", "bitbucket_commit_file.test", "commit_sha"),
+					resource.TestCheckResourceAttr(dataSourceName, "author", "Unit test <unit@test.local>"),
+					resource.TestCheckResourceAttr(dataSourceName, "message", "Add changelog"),
+					resource.TestCheckResourceAttr(dataSourceName, "parents.#", "1"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "date"),
+				),
+			},
+		},
+	})
+}
+
+func TestFlattenCommit(t *testing.T) {
+	commit := bitbucket.Commit{
+		Hash:    "abc",
+		Date:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Author:  &bitbucket.Author{Raw: "Unit test <unit@test.local>", User: &bitbucket.Account{Uuid: "{user}"}},
+		Message: "Merge branch\n",
+		Parents: []bitbucket.BaseCommit{{Hash: "def"}, {Hash: "ghi"}},
+	}
+
+	expected := map[string]interface{}{
+		"hash":        "abc",
+		"author":      "Unit test <unit@test.local>",
+		"author_uuid": "{user}",
+		"date":        "2024-01-02T03:04:05Z",
+		"message":     "Merge branch",
+		"parents":     []interface{}{"def", "ghi"},
+	}
+
+	if flat := flattenCommit(commit); !reflect.DeepEqual(flat, expected) {
+		t.Errorf("expected %#v, got %#v", expected, flat)
+	}
+}
+
+func TestDataReadCommit_escapedRevision(t *testing.T) {
+	clients, err := newClients(providerSettings{
+		Username: "user",
+		Password: "password",
+		HTTPClient: &http.Client{Transport: listResourceTestTransport{
+			"/2.0/repositories/ws/repo/commit/release%2F1.0%23x": `{"hash": "4f3b3c2a1d0e", "message": "Release\n"}`,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := dataCommit().Data(nil)
+	d.Set("workspace", "ws")
+	d.Set("repo_slug", "repo")
+	d.Set("revision", "release/1.0#x")
+
+	if diags := dataReadCommit(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error reading the commit: %v", diags)
+	}
+	if d.Id() != "ws/repo/4f3b3c2a1d0e" {
+		t.Errorf("expected ID %q, got %q", "ws/repo/4f3b3c2a1d0e", d.Id())
+	}
+	if message := d.Get("message").(string); message != "Release" {
+		t.Errorf("expected message %q, got %q", "Release", message)
+	}
+}
+
+func testAccBitbucketCommitConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = bitbucket_repository.test.mainbranch
+  filename       = "CHANGELOG.md"
+  content        = "Changes"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "Add changelog"
+}
+
+data "bitbucket_commit" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  revision  = bitbucket_commit_file.test.commit_sha
+}
+`, workspace, rName)
+}
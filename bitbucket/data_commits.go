@@ -0,0 +1,94 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strollby/bitbucket-go-client"
+)
+
+func dataCommits() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataReadCommits,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"repo_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"include": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The branches, tags or commits whose history is listed",
+			},
+			"exclude": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The branches, tags or commits whose history is left out",
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only list the commits that modified this path",
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "The maximum number of commits to list, or 0 for all of them",
+			},
+			"commits": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: commitSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataReadCommits(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+
+	params := url.Values{}
+	params.Set("pagelen", "100")
+	for _, v := range d.Get("include").([]interface{}) {
+		params.Add("include", v.(string))
+	}
+	for _, v := range d.Get("exclude").([]interface{}) {
+		params.Add("exclude", v.(string))
+	}
+	if v, ok := d.GetOk("path"); ok {
+		params.Set("path", v.(string))
+	}
+
+	commits, err := listPaginatedValuesUpTo[bitbucket.Commit](&client, fmt.Sprintf("2.0/repositories/%s/%s/commits?%s", url.PathEscape(workspace), url.PathEscape(repoSlug), params.Encode()), d.Get("limit").(int))
+	if err != nil {
+		return diag.Errorf("error reading Commits (%s/%s): %s", workspace, repoSlug, err)
+	}
+
+	flatCommits := make([]interface{}, 0, len(commits))
+	for _, commit := range commits {
+		flatCommits = append(flatCommits, flattenCommit(commit))
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", workspace, repoSlug))
+	d.Set("commits", flatCommits)
+
+	return nil
+}
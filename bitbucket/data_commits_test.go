@@ -0,0 +1,99 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceCommits_basic(t *testing.T) {
+	dataSourceName := "data.bitbucket_commits.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitsConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "commits.#", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "commits.0.hash", "bitbucket_commit_file.test", "commit_sha"),
+					resource.TestCheckResourceAttr(dataSourceName, "commits.0.message", "Add changelog"),
+				),
+			},
+		},
+	})
+}
+
+func TestDataReadCommits(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = listResourceTestTransport{
+		"/2.0/repositories/ws/repo/commits?exclude=main&include=feature&pagelen=100&path=docs%2FREADME.md":        `{"values": [{"hash": "c3", "parents": [{"hash": "c2"}]}, {"hash": "c2", "parents": [{"hash": "c1"}]}], "next": "https://api.bitbucket.org/2.0/repositories/ws/repo/commits?exclude=main&include=feature&pagelen=100&path=docs%2FREADME.md&page=2"}`,
+		"/2.0/repositories/ws/repo/commits?exclude=main&include=feature&pagelen=100&path=docs%2FREADME.md&page=2": `{"values": [{"hash": "c1"}]}`,
+	}
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	clients, err := newClients(providerSettings{Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for limit, expected := range map[int][]string{0: {"c3", "c2", "c1"}, 2: {"c3", "c2"}} {
+		d := dataCommits().TestResourceData()
+		d.Set("workspace", "ws")
+		d.Set("repo_slug", "repo")
+		d.Set("include", []interface{}{"feature"})
+		d.Set("exclude", []interface{}{"main"})
+		d.Set("path", "docs/README.md")
+		d.Set("limit", limit)
+
+		if diags := dataReadCommits(context.Background(), d, clients); diags.HasError() {
+			t.Fatalf("unexpected error: %v", diags)
+		}
+
+		if got := d.Get("commits.#").(int); got != len(expected) {
+			t.Fatalf("limit %d: expected %d commits, got %d", limit, len(expected), got)
+		}
+		for i, hash := range expected {
+			if got := d.Get(fmt.Sprintf("commits.%d.hash", i)).(string); got != hash {
+				t.Errorf("limit %d: expected commit %d to be %s, got %s", limit, i, hash, got)
+			}
+		}
+	}
+}
+
+func testAccBitbucketCommitsConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = bitbucket_repository.test.mainbranch
+  filename       = "CHANGELOG.md"
+  content        = "Changes"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "Add changelog"
+}
+
+data "bitbucket_commits" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  include   = [bitbucket_commit_file.test.commit_sha]
+  path      = bitbucket_commit_file.test.filename
+}
+`, workspace, rName)
+}
@@ -0,0 +1,166 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataDirectory() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataReadDirectory,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"repo_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The path of the directory to list, the root of the repository by default",
+			},
+			"ref": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The branch, tag or commit to list the directory at, the main branch by default",
+			},
+			"recursive": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"ref_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"entries": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"mimetype": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"executable": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"symlink": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataReadDirectory(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	dir := strings.Trim(d.Get("path").(string), "/")
+
+	hash, err := resolveRef(m.(Clients), workspace, repoSlug, d.Get("ref").(string))
+	if err != nil {
+		return diag.Errorf("error reading Directory (%s/%s/%s): %s", workspace, repoSlug, dir, err)
+	}
+
+	if dir != "" {
+		meta, err := getSrcFileMeta(&client, workspace, repoSlug, hash, dir)
+		if err != nil {
+			return diag.Errorf("error reading Directory (%s/%s/%s): %s", workspace, repoSlug, dir, err)
+		}
+		if meta == nil {
+			return diag.Errorf("directory %s not found in %s/%s at %s", dir, workspace, repoSlug, hash)
+		}
+		if meta.Type != "commit_directory" {
+			return diag.Errorf("%s in %s/%s is not a directory, use the bitbucket_file data source to read files", dir, workspace, repoSlug)
+		}
+	}
+
+	entries, err := listSrcDirectory(&client, workspace, repoSlug, hash, dir, d.Get("recursive").(bool))
+	if err != nil {
+		return diag.Errorf("error reading Directory (%s/%s/%s): %s", workspace, repoSlug, dir, err)
+	}
+
+	flatEntries := make([]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		flatEntries = append(flatEntries, flattenSrcEntry(entry))
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, hash, dir))
+	d.Set("path", dir)
+	d.Set("ref_hash", hash)
+	d.Set("entries", flatEntries)
+
+	return nil
+}
+
+// listSrcDirectory lists the entries of dir in commit, each directory
+// followed by its own entries when recursive.
+func listSrcDirectory(client *Client, workspace, repoSlug, commit, dir string, recursive bool) ([]srcFileMeta, error) {
+	endpoint := srcEndpoint(workspace, repoSlug, commit, dir)
+	if dir != "" {
+		endpoint += "/"
+	}
+
+	values, err := listPaginatedValues[srcFileMeta](client, endpoint+"?pagelen=100")
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []srcFileMeta
+	for _, value := range values {
+		entries = append(entries, value)
+
+		if recursive && value.Type == "commit_directory" {
+			children, err := listSrcDirectory(client, workspace, repoSlug, commit, strings.TrimSuffix(value.Path, "/"), true)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, children...)
+		}
+	}
+
+	return entries, nil
+}
+
+func flattenSrcEntry(entry srcFileMeta) map[string]interface{} {
+	entryType := "file"
+	if entry.Type == "commit_directory" {
+		entryType = "directory"
+	}
+
+	return map[string]interface{}{
+		"path":       strings.TrimSuffix(entry.Path, "/"),
+		"type":       entryType,
+		"size":       entry.Size,
+		"mimetype":   entry.Mimetype,
+		"executable": entry.hasAttribute("executable"),
+		"symlink":    entry.hasAttribute("link"),
+	}
+}
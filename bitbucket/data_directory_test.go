@@ -0,0 +1,102 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceDirectory_recursive(t *testing.T) {
+	dataSourceName := "data.bitbucket_directory.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketDirectoryConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "path", "config"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ref_hash", "bitbucket_commit_files.test", "commit_sha"),
+					resource.TestCheckResourceAttr(dataSourceName, "entries.#", "3"),
+					resource.TestCheckResourceAttr(dataSourceName, "entries.0.path", "config/app.json"),
+					resource.TestCheckResourceAttr(dataSourceName, "entries.0.type", "file"),
+					resource.TestCheckResourceAttr(dataSourceName, "entries.1.path", "config/env"),
+					resource.TestCheckResourceAttr(dataSourceName, "entries.1.type", "directory"),
+					resource.TestCheckResourceAttr(dataSourceName, "entries.2.path", "config/env/prod.json"),
+				),
+			},
+		},
+	})
+}
+
+func TestListSrcDirectory(t *testing.T) {
+	client := &Client{HTTPClient: &http.Client{Transport: listResourceTestTransport{
+		"/2.0/repositories/ws/repo/src/abc/?pagelen=100":        `{"values": [{"path": "README.md", "type": "commit_file", "size": 3}], "next": "https://api.bitbucket.org/2.0/repositories/ws/repo/src/abc/?pagelen=100&page=2"}`,
+		"/2.0/repositories/ws/repo/src/abc/?pagelen=100&page=2": `{"values": [{"path": "docs", "type": "commit_directory"}, {"path": "run.sh", "type": "commit_file", "attributes": ["executable"]}]}`,
+		"/2.0/repositories/ws/repo/src/abc/docs/?pagelen=100":   `{"values": [{"path": "docs/index.md", "type": "commit_file", "size": 5}]}`,
+	}}}
+
+	cases := map[bool][]string{
+		false: {"README.md", "docs", "run.sh"},
+		true:  {"README.md", "docs", "docs/index.md", "run.sh"},
+	}
+
+	for recursive, expected := range cases {
+		entries, err := listSrcDirectory(client, "ws", "repo", "abc", "", recursive)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		var paths []string
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+		if !reflect.DeepEqual(paths, expected) {
+			t.Errorf("recursive %t: expected paths %q, got %q", recursive, expected, paths)
+		}
+	}
+
+	if entry := flattenSrcEntry(srcFileMeta{Path: "run.sh", Type: "commit_file", Attributes: []string{"executable"}}); entry["type"] != "file" || entry["executable"] != true || entry["symlink"] != false {
+		t.Errorf("unexpected entry %#v", entry)
+	}
+}
+
+func testAccBitbucketDirectoryConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_commit_files" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = bitbucket_repository.test.mainbranch
+  commit_message = "Add config"
+
+  files = {
+    "config/app.json"      = "{}"
+    "config/env/prod.json" = "{}"
+  }
+}
+
+data "bitbucket_directory" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  path      = "config/"
+  ref       = bitbucket_commit_files.test.commit_sha
+  recursive = true
+}
+`, workspace, rName)
+}
@@ -0,0 +1,129 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataFile() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataReadFile,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"repo_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"path": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ref": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The branch, tag or commit to read the file at, the main branch by default",
+			},
+			"ref_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"content_base64": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"size": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"mimetype": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"commit": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The last commit that modified the file",
+			},
+		},
+	}
+}
+
+func dataReadFile(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	filePath := d.Get("path").(string)
+
+	hash, err := resolveRef(m.(Clients), workspace, repoSlug, d.Get("ref").(string))
+	if err != nil {
+		return diag.Errorf("error reading File (%s/%s/%s): %s", workspace, repoSlug, filePath, err)
+	}
+
+	meta, err := getSrcFileMeta(&client, workspace, repoSlug, hash, filePath)
+	if err != nil {
+		return diag.Errorf("error reading File (%s/%s/%s): %s", workspace, repoSlug, filePath, err)
+	}
+	if meta == nil {
+		return diag.Errorf("file %s not found in %s/%s at %s", filePath, workspace, repoSlug, hash)
+	}
+	if meta.Type != "commit_file" {
+		return diag.Errorf("%s in %s/%s is not a file, use the bitbucket_directory data source to list directories", filePath, workspace, repoSlug)
+	}
+
+	content, err := getSrcFile(&client, workspace, repoSlug, hash, filePath)
+	if err != nil {
+		return diag.Errorf("error reading File (%s/%s/%s): %s", workspace, repoSlug, filePath, err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, hash, filePath))
+	d.Set("ref_hash", hash)
+	// Binary content is only available base64 encoded.
+	if utf8.Valid(content) {
+		d.Set("content", string(content))
+	} else {
+		d.Set("content", "")
+	}
+	d.Set("content_base64", base64.StdEncoding.EncodeToString(content))
+	d.Set("size", meta.Size)
+	d.Set("mimetype", meta.Mimetype)
+	d.Set("commit", meta.Commit.Hash)
+
+	return nil
+}
+
+// resolveRef returns the hash of the commit ref points to, or the head of the
+// main branch when ref is empty.
+func resolveRef(clients Clients, workspace, repoSlug, ref string) (string, error) {
+	if ref == "" {
+		client := clients.httpClient
+
+		repo, err := getRepository(&client, workspace, repoSlug)
+		if err != nil {
+			return "", err
+		}
+		if repo == nil {
+			return "", fmt.Errorf("repository %s/%s not found", workspace, repoSlug)
+		}
+		if repo.Mainbranch == nil {
+			return "", fmt.Errorf("repository %s/%s has no main branch", workspace, repoSlug)
+		}
+
+		ref = repo.Mainbranch.Name
+	}
+
+	return resolveCommit(clients, workspace, repoSlug, ref)
+}
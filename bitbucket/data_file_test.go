@@ -0,0 +1,64 @@
+package bitbucket
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceFile_basic(t *testing.T) {
+	dataSourceName := "data.bitbucket_file.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketFileConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "content", `{"replicas":3}`),
+					resource.TestCheckResourceAttr(dataSourceName, "content_base64", "eyJyZXBsaWNhcyI6M30="),
+					resource.TestCheckResourceAttr(dataSourceName, "size", "14"),
+					resource.TestCheckResourceAttr(dataSourceName, "mimetype", "application/json"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "commit", "bitbucket_commit_file.test", "commit_sha"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "ref_hash", "bitbucket_commit_file.test", "commit_sha"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketFileConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = bitbucket_repository.test.mainbranch
+  filename       = "config/app.json"
+  content        = jsonencode({ replicas = 3 })
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "Add config"
+}
+
+data "bitbucket_file" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  path      = bitbucket_commit_file.test.filename
+
+  depends_on = [bitbucket_commit_file.test]
+}
+`, workspace, rName)
+}
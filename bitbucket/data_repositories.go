@@ -0,0 +1,218 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strollby/bitbucket-go-client"
+)
+
+func dataRepositories() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataReadRepositories,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"project_key": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_prefix": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"is_private": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"updated_after": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.IsRFC3339Time,
+			},
+			"sort": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"repositories": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"slug": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"full_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"project_key": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"project_uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"clone_https": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"clone_ssh": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mainbranch": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"language": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"is_private": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"updated_on": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataReadRepositories(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	query := repositoriesQuery(d)
+
+	params := url.Values{}
+	params.Set("pagelen", "100")
+	if query != "" {
+		params.Set("q", query)
+	}
+	if v, ok := d.GetOk("sort"); ok {
+		params.Set("sort", v.(string))
+	}
+
+	repos, err := listPaginatedValues[bitbucket.Repository](&client, fmt.Sprintf("2.0/repositories/%s?%s", url.PathEscape(workspace), params.Encode()))
+	if err != nil {
+		return diag.Errorf("error reading Repositories (%s): %s", workspace, err)
+	}
+
+	// BBQL has no prefix operator, so name_prefix is narrowed down with a
+	// substring match and filtered exactly here.
+	namePrefix := strings.ToLower(d.Get("name_prefix").(string))
+
+	var repositories []interface{}
+	for _, repo := range repos {
+		if !strings.HasPrefix(strings.ToLower(repo.Name), namePrefix) {
+			continue
+		}
+
+		repositories = append(repositories, flattenRepositorySummary(repo))
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", workspace, query))
+	d.Set("repositories", repositories)
+
+	return nil
+}
+
+// repositoriesQuery combines the query argument with the typed filters into a
+// single BBQL query.
+func repositoriesQuery(d *schema.ResourceData) string {
+	var filters []string
+
+	if v, ok := d.GetOk("query"); ok {
+		filters = append(filters, fmt.Sprintf("(%s)", v.(string)))
+	}
+
+	if v, ok := d.GetOk("project_key"); ok {
+		filters = append(filters, fmt.Sprintf("project.key = %s", bbqlString(v.(string))))
+	}
+
+	if v, ok := d.GetOk("name_prefix"); ok {
+		filters = append(filters, fmt.Sprintf("name ~ %s", bbqlString(v.(string))))
+	}
+
+	// nolint:staticcheck
+	if v, ok := d.GetOkExists("is_private"); ok {
+		filters = append(filters, fmt.Sprintf("is_private = %t", v.(bool)))
+	}
+
+	if v, ok := d.GetOk("updated_after"); ok {
+		filters = append(filters, fmt.Sprintf("updated_on > %s", v.(string)))
+	}
+
+	return strings.Join(filters, " AND ")
+}
+
+// bbqlString quotes s as a BBQL string literal.
+func bbqlString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+func flattenRepositorySummary(repo bitbucket.Repository) map[string]interface{} {
+	repository := map[string]interface{}{
+		"slug":       repo.Slug,
+		"name":       repo.Name,
+		"full_name":  repo.FullName,
+		"uuid":       repo.Uuid,
+		"language":   repo.Language,
+		"is_private": repo.IsPrivate,
+	}
+
+	if !repo.UpdatedOn.IsZero() {
+		repository["updated_on"] = repo.UpdatedOn.Format(time.RFC3339)
+	}
+
+	if repo.Project != nil {
+		repository["project_key"] = repo.Project.Key
+		repository["project_uuid"] = repo.Project.Uuid
+	}
+
+	if repo.Mainbranch != nil {
+		repository["mainbranch"] = repo.Mainbranch.Name
+	}
+
+	if repo.Links != nil {
+		for _, cloneURL := range repo.Links.Clone {
+			switch cloneURL.Name {
+			case "https":
+				repository["clone_https"] = cloneURL.Href
+			case "ssh":
+				repository["clone_ssh"] = cloneURL.Href
+			}
+		}
+	}
+
+	return repository
+}
@@ -0,0 +1,70 @@
+package bitbucket
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccDataSourceRepositories_basic(t *testing.T) {
+	dataSourceName := "data.bitbucket_repositories.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoriesConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "repositories.#", "1"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "repositories.0.uuid", "bitbucket_repository.test", "uuid"),
+					resource.TestCheckResourceAttr(dataSourceName, "repositories.0.slug", rName),
+					resource.TestCheckResourceAttr(dataSourceName, "repositories.0.is_private", "true"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "repositories.0.project_key"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "repositories.0.clone_https"),
+					resource.TestCheckResourceAttrSet(dataSourceName, "repositories.0.clone_ssh"),
+				),
+			},
+		},
+	})
+}
+
+func TestRepositoriesQuery(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, dataRepositories().Schema, map[string]interface{}{
+		"workspace":     "workspace",
+		"query":         `language = "go" OR language = "python"`,
+		"project_key":   "PROJ",
+		"name_prefix":   `tf-"test`,
+		"is_private":    false,
+		"updated_after": "2024-01-01T00:00:00Z",
+	})
+
+	expected := `(language = "go" OR language = "python") AND project.key = "PROJ" AND name ~ "tf-\"test" AND is_private = false AND updated_on > 2024-01-01T00:00:00Z`
+	if got := repositoriesQuery(d); got != expected {
+		t.Errorf("expected query %s, got %s", expected, got)
+	}
+}
+
+func testAccBitbucketRepositoriesConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  deletion_protection = false
+}
+
+data "bitbucket_repositories" "test" {
+  workspace   = %[1]q
+  name_prefix = bitbucket_repository.test.name
+  is_private  = true
+  sort        = "-updated_on"
+}
+`, workspace, rName)
+}
@@ -0,0 +1,106 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataRepository() *schema.Resource {
+	dataSchema := dataSourceSchemaFromResourceSchema(resourceRepository().Schema)
+	delete(dataSchema, "initialize")
+	delete(dataSchema, "import_source")
+	delete(dataSchema, "avatar_file")
+	delete(dataSchema, "avatar_base64")
+	delete(dataSchema, "avatar_sha256")
+	delete(dataSchema, "transfer_pending")
+	delete(dataSchema, "deletion_protection")
+	delete(dataSchema, "on_destroy")
+
+	dataSchema["workspace"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"full_name"},
+	}
+	dataSchema["slug"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"full_name", "uuid"},
+		RequiredWith:  []string{"workspace"},
+	}
+	dataSchema["full_name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"slug", "full_name", "uuid"},
+	}
+	dataSchema["uuid"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		Computed:      true,
+		ConflictsWith: []string{"full_name", "slug"},
+	}
+
+	return &schema.Resource{
+		ReadWithoutTimeout: dataReadRepository,
+		Schema:             dataSchema,
+	}
+}
+
+func dataReadRepository(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var workspace, repoSlug string
+
+	switch {
+	case d.Get("full_name").(string) != "":
+		var err error
+		workspace, repoSlug, err = splitFullName(d.Get("full_name").(string))
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	case d.Get("slug").(string) != "":
+		workspace = d.Get("workspace").(string)
+		repoSlug = d.Get("slug").(string)
+	default:
+		// The pipelines config and override settings cannot be looked up by
+		// UUID alone, so resolve the repository's full name first.
+		c := m.(Clients).genClient
+
+		lookupWorkspace := d.Get("workspace").(string)
+		if lookupWorkspace == "" {
+			lookupWorkspace = "{}"
+		}
+
+		repoRes, res, err := c.ApiClient.RepositoriesApi.RepositoriesWorkspaceRepoSlugGet(c.AuthContext, d.Get("uuid").(string), lookupWorkspace)
+		if res != nil && res.StatusCode == http.StatusNotFound {
+			return diag.Errorf("repository %s not found", d.Get("uuid").(string))
+		}
+		if err := handleClientError(err); err != nil {
+			return diag.FromErr(err)
+		}
+
+		workspace, repoSlug, err = splitFullName(repoRes.FullName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	repo, diags := readRepository(ctx, d, m, workspace, repoSlug)
+	if diags.HasError() {
+		return diags
+	}
+
+	if repo == nil {
+		return diag.Errorf("repository %s/%s not found", workspace, repoSlug)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", workspace, d.Get("slug").(string)))
+	d.Set("workspace", workspace)
+	d.Set("full_name", fmt.Sprintf("%s/%s", workspace, d.Get("slug").(string)))
+
+	return nil
+}
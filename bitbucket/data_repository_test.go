@@ -0,0 +1,65 @@
+package bitbucket
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRepository_basic(t *testing.T) {
+	bySlug := "data.bitbucket_repository.by_slug"
+	byFullName := "data.bitbucket_repository.by_full_name"
+	byUUID := "data.bitbucket_repository.by_uuid"
+	resourceName := "bitbucket_repository.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoryDataConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(bySlug, "uuid", resourceName, "uuid"),
+					resource.TestCheckResourceAttrPair(bySlug, "clone_ssh", resourceName, "clone_ssh"),
+					resource.TestCheckResourceAttrPair(bySlug, "project_key", resourceName, "project_key"),
+					resource.TestCheckResourceAttr(bySlug, "pipelines_enabled", "true"),
+					resource.TestCheckResourceAttr(bySlug, "full_name", fmt.Sprintf("%s/%s", workspace, rName)),
+					resource.TestCheckResourceAttrPair(byFullName, "uuid", resourceName, "uuid"),
+					resource.TestCheckResourceAttr(byFullName, "workspace", workspace),
+					resource.TestCheckResourceAttr(byUUID, "slug", rName),
+					resource.TestCheckResourceAttr(byUUID, "workspace", workspace),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketRepositoryDataConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner             = %[1]q
+  name              = %[2]q
+  pipelines_enabled = true
+
+  deletion_protection = false
+}
+
+data "bitbucket_repository" "by_slug" {
+  workspace = bitbucket_repository.test.owner
+  slug      = bitbucket_repository.test.slug
+}
+
+data "bitbucket_repository" "by_full_name" {
+  full_name = bitbucket_repository.test.id
+}
+
+data "bitbucket_repository" "by_uuid" {
+  uuid = bitbucket_repository.test.uuid
+}
+`, workspace, rName)
+}
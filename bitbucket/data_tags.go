@@ -0,0 +1,116 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strollby/bitbucket-go-client"
+)
+
+func dataTags() *schema.Resource {
+	return &schema.Resource{
+		ReadWithoutTimeout: dataReadTags,
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"repo_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"pattern": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateGlob,
+			},
+			"sort": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"tags": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"target_hash": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"message": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tagger": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataReadTags(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	pattern := d.Get("pattern").(string)
+
+	params := url.Values{}
+	params.Set("pagelen", "100")
+	// BBQL has no glob operator, so the pattern is narrowed down with a
+	// substring match of its literal prefix and matched exactly here.
+	if prefix := patternPrefix(pattern); prefix != "" {
+		params.Set("q", fmt.Sprintf("name ~ %s", bbqlString(prefix)))
+	}
+	if v, ok := d.GetOk("sort"); ok {
+		params.Set("sort", v.(string))
+	}
+
+	tags, err := listPaginatedValues[bitbucket.Tag](&client, fmt.Sprintf("2.0/repositories/%s/%s/refs/tags?%s", url.PathEscape(workspace), url.PathEscape(repoSlug), params.Encode()))
+	if err != nil {
+		return diag.Errorf("error reading Tags (%s/%s): %s", workspace, repoSlug, err)
+	}
+
+	var flatTags []interface{}
+	for _, tag := range tags {
+		if pattern != "" {
+			if !matchGlob(pattern, tag.Name) {
+				continue
+			}
+		}
+
+		flatTags = append(flatTags, flattenTag(tag))
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", workspace, repoSlug, pattern))
+	d.Set("tags", flatTags)
+
+	return nil
+}
+
+// patternPrefix returns the literal part of pattern before its first special
+// character.
+func patternPrefix(pattern string) string {
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		return pattern[:i]
+	}
+
+	return pattern
+}
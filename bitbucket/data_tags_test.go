@@ -0,0 +1,129 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceTags_basic(t *testing.T) {
+	dataSourceName := "data.bitbucket_tags.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { testAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketTagsConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dataSourceName, "tags.#", "2"),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.0.name", "v1.9.0"),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.1.name", "v1.10.0"),
+					resource.TestCheckResourceAttrPair(dataSourceName, "tags.1.target_hash", "bitbucket_tag.test[\"v1.10.0\"]", "target_hash"),
+					resource.TestCheckResourceAttr(dataSourceName, "tags.1.message", "Release v1.10.0"),
+				),
+			},
+		},
+	})
+}
+
+func TestPatternPrefix(t *testing.T) {
+	cases := map[string]string{
+		"":            "",
+		"v1.0.0":      "v1.0.0",
+		"v1.*":        "v1.",
+		"release-?.0": "release-",
+		"[vV]1.*":     "",
+		`v1\*`:        "v1",
+	}
+
+	for pattern, expected := range cases {
+		if got := patternPrefix(pattern); got != expected {
+			t.Errorf("%q: expected prefix %q, got %q", pattern, expected, got)
+		}
+	}
+}
+
+func TestDataReadTags_pattern(t *testing.T) {
+	clients, err := newClients(providerSettings{
+		Username: "user",
+		Password: "password",
+		HTTPClient: &http.Client{Transport: listResourceTestTransport{
+			"/2.0/repositories/ws/repo/refs/tags?pagelen=100&q=name+~+%22release%2F%22": `{"values": [
+				{"name": "release/1.0", "target": {"hash": "1111111"}},
+				{"name": "release/1.0/hotfix", "target": {"hash": "2222222"}},
+				{"name": "pre-release/1.0", "target": {"hash": "3333333"}}
+			]}`,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	cases := map[string][]string{
+		// As in file paths, * does not match a slash, but ** matches
+		// any number of segments.
+		"release/*":  {"release/1.0"},
+		"release/**": {"release/1.0", "release/1.0/hotfix"},
+	}
+
+	for pattern, expected := range cases {
+		t.Run(pattern, func(t *testing.T) {
+			d := dataTags().Data(nil)
+			d.Set("workspace", "ws")
+			d.Set("repo_slug", "repo")
+			d.Set("pattern", pattern)
+
+			if diags := dataReadTags(context.Background(), d, clients); diags.HasError() {
+				t.Fatalf("unexpected error reading the tags: %v", diags)
+			}
+
+			var names []string
+			for _, tag := range d.Get("tags").([]interface{}) {
+				names = append(names, tag.(map[string]interface{})["name"].(string))
+			}
+			if !reflect.DeepEqual(names, expected) {
+				t.Errorf("expected tags %v, got %v", expected, names)
+			}
+		})
+	}
+}
+
+func testAccBitbucketTagsConfig(workspace, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_tag" "test" {
+  for_each = toset(["v1.9.0", "v1.10.0", "v2.0.0"])
+
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  name      = each.key
+  target    = "main"
+  message   = "Release ${each.key}"
+}
+
+data "bitbucket_tags" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  pattern   = "v1.*"
+  sort      = "name"
+
+  depends_on = [bitbucket_tag.test]
+}
+`, workspace, rName)
+}
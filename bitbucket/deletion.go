@@ -0,0 +1,121 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+const (
+	onDestroyDelete  = "delete"
+	onDestroyArchive = "archive"
+)
+
+// deletionSchema returns the deletion_protection and on_destroy arguments of
+// resources whose deletion loses history, such as repositories and projects.
+func deletionSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"deletion_protection": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"on_destroy": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      onDestroyDelete,
+			ValidateFunc: validation.StringInSlice([]string{onDestroyDelete, onDestroyArchive}, false),
+		},
+	}
+}
+
+// importDeletionDefaults wraps an import function so imported resources get
+// the defaults of deletionSchema, which Read cannot know, instead of an
+// update on the first apply.
+func importDeletionDefaults(next schema.StateContextFunc) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		d.Set("deletion_protection", true)
+		d.Set("on_destroy", onDestroyDelete)
+		return next(ctx, d, m)
+	}
+}
+
+// checkDeletionProtection returns an error diagnostic when d may not be
+// deleted. Archiving is always allowed, since it keeps the history.
+func checkDeletionProtection(d *schema.ResourceData, kind string) diag.Diagnostics {
+	if d.Get("on_destroy").(string) == onDestroyArchive || !d.Get("deletion_protection").(bool) {
+		return nil
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("%s is protected from deletion", kind),
+		Detail: fmt.Sprintf("%s %s has deletion_protection enabled. Deleting it would permanently lose its history. "+
+			"To delete it, set deletion_protection = false and apply before destroying it, "+
+			"or set on_destroy = %q to remove it from the state without deleting it.", kind, d.Id(), onDestroyArchive),
+		AttributePath: cty.GetAttrPath("deletion_protection"),
+	}}
+}
+
+// PermissionConfig is an explicit user or group permission of a repository
+// or project.
+type PermissionConfig struct {
+	Permission string `json:"permission"`
+	User       *struct {
+		UUID string `json:"uuid"`
+	} `json:"user,omitempty"`
+	Group *struct {
+		Slug string `json:"slug"`
+	} `json:"group,omitempty"`
+}
+
+// revokeWritePermissions downgrades every explicit write and admin permission
+// under the permissions-config endpoint to read. Permissions inherited from a
+// project or the workspace, and those of workspace administrators, are left
+// as they are: they are not listed there and cannot be changed per repository.
+func revokeWritePermissions(client *Client, permissionsConfig string) error {
+	read, err := json.Marshal(&PermissionConfig{Permission: "read"})
+	if err != nil {
+		return err
+	}
+
+	for _, kind := range []string{"users", "groups"} {
+		permissions, err := listPaginatedValues[PermissionConfig](client, fmt.Sprintf("%s/%s?pagelen=100", permissionsConfig, kind))
+		if err != nil {
+			return err
+		}
+
+		for _, permission := range permissions {
+			if permission.Permission != "write" && permission.Permission != "admin" {
+				continue
+			}
+
+			var subject string
+			switch {
+			case permission.User != nil:
+				subject = permission.User.UUID
+			case permission.Group != nil:
+				subject = permission.Group.Slug
+			default:
+				continue
+			}
+
+			log.Printf("[DEBUG] Revoking %s permission of %s on %s", permission.Permission, subject, permissionsConfig)
+
+			_, err := client.Put(fmt.Sprintf("%s/%s/%s", permissionsConfig, kind, url.PathEscape(subject)), bytes.NewBuffer(read))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
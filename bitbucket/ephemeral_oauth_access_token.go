@@ -0,0 +1,128 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	oauth2bitbucket "golang.org/x/oauth2/bitbucket"
+	oauth2clientcreds "golang.org/x/oauth2/clientcredentials"
+)
+
+func newEphemeralOAuthAccessToken() ephemeral.EphemeralResource {
+	return &ephemeralOAuthAccessToken{}
+}
+
+type ephemeralOAuthAccessToken struct {
+	clients *Clients
+}
+
+type ephemeralOAuthAccessTokenModel struct {
+	OAuthClientID     types.String `tfsdk:"oauth_client_id"`
+	OAuthClientSecret types.String `tfsdk:"oauth_client_secret"`
+	AccessToken       types.String `tfsdk:"access_token"`
+	TokenType         types.String `tfsdk:"token_type"`
+	ExpiresAt         types.String `tfsdk:"expires_at"`
+	Scopes            types.String `tfsdk:"scopes"`
+}
+
+func (e *ephemeralOAuthAccessToken) Metadata(ctx context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_oauth_access_token"
+}
+
+func (e *ephemeralOAuthAccessToken) Schema(ctx context.Context, req ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"oauth_client_id": schema.StringAttribute{
+				Optional: true,
+			},
+			"oauth_client_secret": schema.StringAttribute{
+				Optional:  true,
+				Sensitive: true,
+			},
+			"access_token": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+			"token_type": schema.StringAttribute{
+				Computed: true,
+			},
+			"expires_at": schema.StringAttribute{
+				Computed: true,
+			},
+			"scopes": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (e *ephemeralOAuthAccessToken) Configure(ctx context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	clients, ok := req.ProviderData.(Clients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected Clients, got %T", req.ProviderData))
+		return
+	}
+
+	e.clients = &clients
+}
+
+func (e *ephemeralOAuthAccessToken) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data ephemeralOAuthAccessTokenModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var config *oauth2clientcreds.Config
+
+	if data.OAuthClientID.IsNull() != data.OAuthClientSecret.IsNull() {
+		resp.Diagnostics.AddError("Incomplete OAuth client credentials",
+			"oauth_client_id and oauth_client_secret must be set together.")
+		return
+	}
+
+	if !data.OAuthClientID.IsNull() {
+		config = &oauth2clientcreds.Config{
+			ClientID:     data.OAuthClientID.ValueString(),
+			ClientSecret: data.OAuthClientSecret.ValueString(),
+			TokenURL:     oauth2bitbucket.Endpoint.TokenURL,
+		}
+	} else if e.clients != nil {
+		config = e.clients.oauthConfig
+	}
+
+	if config == nil {
+		resp.Diagnostics.AddError("Missing OAuth client credentials",
+			"bitbucket_oauth_access_token requires oauth_client_id and oauth_client_secret, "+
+				"either on the provider or on the ephemeral resource itself.")
+		return
+	}
+
+	token, err := config.Token(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Error requesting OAuth access token", err.Error())
+		return
+	}
+
+	data.AccessToken = types.StringValue(token.AccessToken)
+	data.TokenType = types.StringValue(token.Type())
+	data.ExpiresAt = types.StringNull()
+	if !token.Expiry.IsZero() {
+		data.ExpiresAt = types.StringValue(token.Expiry.UTC().Format(time.RFC3339))
+	}
+	data.Scopes = types.StringNull()
+	if scopes, ok := token.Extra("scopes").(string); ok {
+		data.Scopes = types.StringValue(scopes)
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
@@ -0,0 +1,60 @@
+package bitbucket
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccBitbucketEphemeralOAuthAccessToken_basic(t *testing.T) {
+	owner := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+	resourceName := "bitbucket_repository_variable.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckOAuthClient(t)
+		},
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckBitbucketRepositoryVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketEphemeralOAuthAccessTokenConfig(owner, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", ""),
+					resource.TestCheckResourceAttr(resourceName, "value_in_sync", "true"),
+					resource.TestCheckNoResourceAttr(resourceName, "value_hash"),
+				),
+			},
+		},
+	})
+}
+
+func testAccBitbucketEphemeralOAuthAccessTokenConfig(team, rName string) string {
+	return fmt.Sprintf(`
+ephemeral "bitbucket_oauth_access_token" "test" {
+  oauth_client_id     = %[3]q
+  oauth_client_secret = %[4]q
+}
+
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  deletion_protection = false
+}
+
+resource "bitbucket_repository_variable" "test" {
+  key              = "BITBUCKET_TOKEN"
+  value_wo         = ephemeral.bitbucket_oauth_access_token.test.access_token
+  value_wo_version = 1
+  secured          = true
+  repository       = bitbucket_repository.test.id
+}
+`, team, rName, os.Getenv("BITBUCKET_OAUTH_CLIENT_ID"), os.Getenv("BITBUCKET_OAUTH_CLIENT_SECRET"))
+}
@@ -2,6 +2,7 @@ package bitbucket
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/strollby/bitbucket-go-client"
@@ -12,7 +13,7 @@ func handleClientError(err error) error {
 	if ok {
 		var httpError bitbucket.ModelError
 		if err := json.Unmarshal(httpErr.Body(), &httpError); err != nil {
-			return fmt.Errorf(string(httpErr.Body()))
+			return errors.New(string(httpErr.Body()))
 		}
 
 		return fmt.Errorf("%s: %s", httpErr.Error(), httpError.Error_.Message)
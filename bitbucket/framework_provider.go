@@ -0,0 +1,135 @@
+package bitbucket
+
+import (
+	"context"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// ProtoV5ProviderServerFactory muxes the SDK provider with the framework
+// provider, which serves the features the SDK cannot, such as ephemeral
+// resources and list resources. The SDK provider keeps some attributes in
+// private state, see privateStateAttributes.
+func ProtoV5ProviderServerFactory(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	providers := []func() tfprotov5.ProviderServer{
+		newPrivateStateServer(Provider()),
+		providerserver.NewProtocol5(NewFrameworkProvider()),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
+
+// NewFrameworkProvider returns the framework half of the provider. Its schema
+// must stay identical to the one declared in Provider.
+func NewFrameworkProvider() provider.Provider {
+	return &frameworkProvider{}
+}
+
+type frameworkProvider struct{}
+
+type frameworkProviderModel struct {
+	Username          types.String `tfsdk:"username"`
+	Password          types.String `tfsdk:"password"`
+	OAuthClientID     types.String `tfsdk:"oauth_client_id"`
+	OAuthClientSecret types.String `tfsdk:"oauth_client_secret"`
+	OAuthToken        types.String `tfsdk:"oauth_token"`
+}
+
+func (p *frameworkProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "bitbucket"
+}
+
+func (p *frameworkProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				Optional: true,
+			},
+			"password": schema.StringAttribute{
+				Optional: true,
+			},
+			"oauth_client_id": schema.StringAttribute{
+				Optional: true,
+			},
+			"oauth_client_secret": schema.StringAttribute{
+				Optional: true,
+			},
+			"oauth_token": schema.StringAttribute{
+				Optional: true,
+			},
+		},
+	}
+}
+
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config frameworkProviderModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	clients, err := newClients(providerSettings{
+		Username:          stringValueOrEnv(config.Username, "BITBUCKET_USERNAME"),
+		Password:          stringValueOrEnv(config.Password, "BITBUCKET_PASSWORD"),
+		OAuthClientID:     stringValueOrEnv(config.OAuthClientID, "BITBUCKET_OAUTH_CLIENT_ID"),
+		OAuthClientSecret: stringValueOrEnv(config.OAuthClientSecret, "BITBUCKET_OAUTH_CLIENT_SECRET"),
+		OAuthToken:        stringValueOrEnv(config.OAuthToken, "BITBUCKET_OAUTH_TOKEN"),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error configuring Bitbucket provider", err.Error())
+		return
+	}
+
+	resp.EphemeralResourceData = clients
+	resp.ListResourceData = clients
+}
+
+func (p *frameworkProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) EphemeralResources(ctx context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		newEphemeralOAuthAccessToken,
+	}
+}
+
+func (p *frameworkProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		newRepositoryListResource,
+		newHookListResource,
+		newBranchRestrictionListResource,
+		newRepositoryVariableListResource,
+		newWorkspaceVariableListResource,
+		newDeploymentVariableListResource,
+	}
+}
+
+// stringValueOrEnv mirrors schema.EnvDefaultFunc for framework attributes.
+func stringValueOrEnv(v types.String, key string) string {
+	if v.IsNull() || v.IsUnknown() {
+		return os.Getenv(key)
+	}
+
+	return v.ValueString()
+}
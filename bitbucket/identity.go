@@ -0,0 +1,101 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceIdentity describes the identity of a managed resource: its ordered
+// identity attributes and how they are joined into the ID accepted by the
+// resource's importer. It lets `import` blocks use structured identities such
+// as `{workspace, repo_slug}` instead of the opaque import ID.
+type resourceIdentity struct {
+	attributes []string
+	importID   func(values []string) string
+}
+
+// newResourceIdentity returns an identity whose import ID is its attribute
+// values joined by separator.
+func newResourceIdentity(separator string, attributes ...string) resourceIdentity {
+	return resourceIdentity{
+		attributes: attributes,
+		importID: func(values []string) string {
+			return strings.Join(values, separator)
+		},
+	}
+}
+
+func (ri resourceIdentity) identitySchema() *schema.ResourceIdentity {
+	return &schema.ResourceIdentity{
+		SchemaFunc: func() map[string]*schema.Schema {
+			identitySchema := make(map[string]*schema.Schema, len(ri.attributes))
+			for _, attribute := range ri.attributes {
+				identitySchema[attribute] = &schema.Schema{
+					Type:              schema.TypeString,
+					RequiredForImport: true,
+				}
+			}
+			return identitySchema
+		},
+	}
+}
+
+// importer wraps the import function of a resource so it also accepts an
+// identity, which is converted to the import ID that next understands.
+func (ri resourceIdentity) importer(next schema.StateContextFunc) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		StateContext: func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			if d.Id() == "" {
+				values, err := ri.values(d)
+				if err != nil {
+					return nil, err
+				}
+				d.SetId(ri.importID(values))
+			}
+
+			return next(ctx, d, m)
+		},
+	}
+}
+
+// values returns the identity attribute values of d, in attribute order.
+func (ri resourceIdentity) values(d *schema.ResourceData) ([]string, error) {
+	identity, err := d.Identity()
+	if err != nil {
+		return nil, fmt.Errorf("error getting identity: %w", err)
+	}
+
+	values := make([]string, len(ri.attributes))
+	for i, attribute := range ri.attributes {
+		v, ok := identity.GetOk(attribute)
+		if !ok {
+			return nil, fmt.Errorf("expected identity to contain %s", attribute)
+		}
+		values[i] = v.(string)
+	}
+
+	return values, nil
+}
+
+// set records the identity of d. Values are given in attribute order.
+func (ri resourceIdentity) set(d *schema.ResourceData, values ...string) error {
+	if len(values) != len(ri.attributes) {
+		return fmt.Errorf("expected %d identity values (%s), got %d", len(ri.attributes), strings.Join(ri.attributes, ", "), len(values))
+	}
+
+	identity, err := d.Identity()
+	if err != nil {
+		return fmt.Errorf("error getting identity: %w", err)
+	}
+
+	for i, attribute := range ri.attributes {
+		if err := identity.Set(attribute, values[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
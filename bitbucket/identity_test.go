@@ -0,0 +1,60 @@
+package bitbucket
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResourceIdentity_allResources(t *testing.T) {
+	for name, r := range Provider().ResourcesMap {
+		if r.Identity == nil {
+			t.Errorf("%s: expected an identity schema", name)
+		}
+		if r.Importer == nil {
+			t.Errorf("%s: expected an importer", name)
+		}
+	}
+}
+
+func TestResourceIdentity_importer(t *testing.T) {
+	r := resourceRepositoryUserPermission()
+	d := r.Data(nil)
+
+	if err := repositoryUserPermissionIdentity.set(d, "workspace", "repo", "{user}"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	imported, err := r.Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if got, want := imported[0].Id(), "workspace:repo:{user}"; got != want {
+		t.Errorf("expected ID %q, got %q", want, got)
+	}
+}
+
+func TestResourceIdentity_importerCommitFile(t *testing.T) {
+	r := resourceCommitFile()
+	d := r.Data(nil)
+
+	if err := commitFileIdentity.set(d, "workspace", "repo", "feature/x", "docs/README.md"); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	imported, err := r.Importer.StateContext(context.Background(), d, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for attribute, want := range map[string]string{
+		"workspace": "workspace",
+		"repo_slug": "repo",
+		"branch":    "feature/x",
+		"filename":  "docs/README.md",
+	} {
+		if got := imported[0].Get(attribute).(string); got != want {
+			t.Errorf("expected %s %q, got %q", attribute, want, got)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+)
+
+func newBranchRestrictionListResource() list.ListResource {
+	return &sdkListResource{
+		typeName:   "_branch_restriction",
+		resource:   resourceBranchRestriction(),
+		identity:   branchRestrictionIdentity,
+		attributes: []string{"workspace", "repo_slug"},
+		list: func(client *Client, config map[string]string) ([]listedResource, error) {
+			workspace, repoSlug := config["workspace"], config["repo_slug"]
+
+			restrictions, err := listPaginatedValues[BranchRestriction](client, fmt.Sprintf("2.0/repositories/%s/%s/branch-restrictions?pagelen=100",
+				url.PathEscape(workspace),
+				url.PathEscape(repoSlug),
+			))
+			if err != nil {
+				return nil, err
+			}
+
+			listed := make([]listedResource, 0, len(restrictions))
+			for _, restriction := range restrictions {
+				target := restriction.Pattern
+				if restriction.BranchMatchkind == "branching_model" {
+					target = restriction.BranchType
+				}
+
+				listed = append(listed, listedResource{
+					identity:    []string{workspace, repoSlug, strconv.Itoa(restriction.ID)},
+					displayName: fmt.Sprintf("%s on %s", restriction.Kind, target),
+				})
+			}
+			return listed, nil
+		},
+	}
+}
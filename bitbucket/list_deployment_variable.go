@@ -0,0 +1,38 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+)
+
+func newDeploymentVariableListResource() list.ListResource {
+	return &sdkListResource{
+		typeName:   "_deployment_variable",
+		resource:   resourceDeploymentVariable(),
+		identity:   deploymentVariableIdentity,
+		attributes: []string{"workspace", "repo_slug", "deployment_uuid"},
+		list: func(client *Client, config map[string]string) ([]listedResource, error) {
+			workspace, repoSlug, deployment := config["workspace"], config["repo_slug"], config["deployment_uuid"]
+
+			variables, err := listPaginatedValues[listedPipelineVariable](client, fmt.Sprintf("2.0/repositories/%s/%s/deployments_config/environments/%s/variables?pagelen=100",
+				url.PathEscape(workspace),
+				url.PathEscape(repoSlug),
+				url.PathEscape(deployment),
+			))
+			if err != nil {
+				return nil, err
+			}
+
+			listed := make([]listedResource, 0, len(variables))
+			for _, variable := range variables {
+				listed = append(listed, listedResource{
+					identity:    []string{workspace, repoSlug, deployment, variable.UUID},
+					displayName: variable.Key,
+				})
+			}
+			return listed, nil
+		},
+	}
+}
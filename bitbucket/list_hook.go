@@ -0,0 +1,37 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+)
+
+func newHookListResource() list.ListResource {
+	return &sdkListResource{
+		typeName:   "_hook",
+		resource:   resourceHook(),
+		identity:   hookIdentity,
+		attributes: []string{"workspace", "repo_slug"},
+		list: func(client *Client, config map[string]string) ([]listedResource, error) {
+			workspace, repoSlug := config["workspace"], config["repo_slug"]
+
+			hooks, err := listPaginatedValues[Hook](client, fmt.Sprintf("2.0/repositories/%s/%s/hooks?pagelen=100",
+				url.PathEscape(workspace),
+				url.PathEscape(repoSlug),
+			))
+			if err != nil {
+				return nil, err
+			}
+
+			listed := make([]listedResource, 0, len(hooks))
+			for _, hook := range hooks {
+				listed = append(listed, listedResource{
+					identity:    []string{workspace, repoSlug, hook.UUID},
+					displayName: fmt.Sprintf("%s (%s)", hook.Description, hook.URL),
+				})
+			}
+			return listed, nil
+		},
+	}
+}
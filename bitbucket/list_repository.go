@@ -0,0 +1,37 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+)
+
+func newRepositoryListResource() list.ListResource {
+	return &sdkListResource{
+		typeName:   "_repository",
+		resource:   resourceRepository(),
+		identity:   repositoryIdentity,
+		attributes: []string{"workspace"},
+		list: func(client *Client, config map[string]string) ([]listedResource, error) {
+			workspace := config["workspace"]
+
+			repositories, err := listPaginatedValues[struct {
+				Slug     string `json:"slug"`
+				FullName string `json:"full_name"`
+			}](client, fmt.Sprintf("2.0/repositories/%s?pagelen=100", url.PathEscape(workspace)))
+			if err != nil {
+				return nil, err
+			}
+
+			listed := make([]listedResource, 0, len(repositories))
+			for _, repository := range repositories {
+				listed = append(listed, listedResource{
+					identity:    []string{workspace, repository.Slug},
+					displayName: repository.FullName,
+				})
+			}
+			return listed, nil
+		},
+	}
+}
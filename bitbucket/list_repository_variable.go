@@ -0,0 +1,43 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+)
+
+// listedPipelineVariable is the part of a pipeline variable needed to list it.
+type listedPipelineVariable struct {
+	UUID string `json:"uuid"`
+	Key  string `json:"key"`
+}
+
+func newRepositoryVariableListResource() list.ListResource {
+	return &sdkListResource{
+		typeName:   "_repository_variable",
+		resource:   resourceRepositoryVariable(),
+		identity:   repositoryVariableIdentity,
+		attributes: []string{"workspace", "repo_slug"},
+		list: func(client *Client, config map[string]string) ([]listedResource, error) {
+			workspace, repoSlug := config["workspace"], config["repo_slug"]
+
+			variables, err := listPaginatedValues[listedPipelineVariable](client, fmt.Sprintf("2.0/repositories/%s/%s/pipelines_config/variables?pagelen=100",
+				url.PathEscape(workspace),
+				url.PathEscape(repoSlug),
+			))
+			if err != nil {
+				return nil, err
+			}
+
+			listed := make([]listedResource, 0, len(variables))
+			for _, variable := range variables {
+				listed = append(listed, listedResource{
+					identity:    []string{workspace, repoSlug, variable.UUID},
+					displayName: variable.Key,
+				})
+			}
+			return listed, nil
+		},
+	}
+}
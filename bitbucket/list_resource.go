@@ -0,0 +1,249 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	listschema "github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// listedResource is a resource instance found by a list resource, identified
+// by its identity values in attribute order.
+type listedResource struct {
+	identity    []string
+	displayName string
+}
+
+// sdkListResource lets `terraform query` enumerate instances of an SDK
+// resource. Instances are found by list and, when Terraform asks for the full
+// resource, read through the resource's own importer and Read function so the
+// results match what `terraform import` would produce.
+type sdkListResource struct {
+	typeName   string
+	resource   *schema.Resource
+	identity   resourceIdentity
+	attributes []string
+	list       func(client *Client, config map[string]string) ([]listedResource, error)
+
+	clients *Clients
+}
+
+var (
+	_ list.ListResourceWithRawV5Schemas = &sdkListResource{}
+	_ list.ListResourceWithConfigure    = &sdkListResource{}
+)
+
+func (l *sdkListResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + l.typeName
+}
+
+func (l *sdkListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	attributes := make(map[string]listschema.Attribute, len(l.attributes))
+	for _, attribute := range l.attributes {
+		attributes[attribute] = listschema.StringAttribute{
+			Required: true,
+		}
+	}
+
+	resp.Schema = listschema.Schema{
+		Attributes: attributes,
+	}
+}
+
+func (l *sdkListResource) RawV5Schemas(ctx context.Context, req list.RawV5SchemaRequest, resp *list.RawV5SchemaResponse) {
+	resp.ProtoV5Schema = l.resource.ProtoSchema(ctx)()
+	if attributes, ok := privateStateAttributes["bitbucket"+l.typeName]; ok {
+		resp.ProtoV5Schema = withoutAttributes(resp.ProtoV5Schema, attributes)
+	}
+	resp.ProtoV5IdentitySchema = l.resource.ProtoIdentitySchema(ctx)()
+}
+
+func (l *sdkListResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	clients, ok := req.ProviderData.(Clients)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data", fmt.Sprintf("expected Clients, got %T", req.ProviderData))
+		return
+	}
+
+	l.clients = &clients
+}
+
+func (l *sdkListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	config := make(map[string]string, len(l.attributes))
+	for _, attribute := range l.attributes {
+		var value types.String
+		diags := req.Config.GetAttribute(ctx, path.Root(attribute), &value)
+		if diags.HasError() {
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+		config[attribute] = value.ValueString()
+	}
+
+	listed, err := l.list(&l.clients.httpClient, config)
+	if err != nil {
+		result := req.NewListResult(ctx)
+		result.Diagnostics.AddError(fmt.Sprintf("Error listing bitbucket%s", l.typeName), err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(result.Diagnostics)
+		return
+	}
+
+	stream.Results = func(push func(list.ListResult) bool) {
+		for i, item := range listed {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+
+			if !push(l.listResult(ctx, req, item)) {
+				return
+			}
+		}
+	}
+}
+
+func (l *sdkListResource) listResult(ctx context.Context, req list.ListRequest, item listedResource) list.ListResult {
+	result := req.NewListResult(ctx)
+	result.DisplayName = item.displayName
+
+	identity := item.identity
+
+	if req.IncludeResource {
+		d, err := l.read(ctx, identity)
+		if err != nil {
+			result.Diagnostics.AddError(fmt.Sprintf("Error reading %s", item.displayName), err.Error())
+			return result
+		}
+
+		if identity, err = l.identity.values(d); err != nil {
+			result.Diagnostics.AddError(fmt.Sprintf("Error reading %s", item.displayName), err.Error())
+			return result
+		}
+
+		if err := l.setResource(ctx, &result, d); err != nil {
+			result.Diagnostics.AddError(fmt.Sprintf("Error reading %s", item.displayName), err.Error())
+			return result
+		}
+	}
+
+	for i, attribute := range l.identity.attributes {
+		result.Diagnostics.Append(result.Identity.SetAttribute(ctx, path.Root(attribute), identity[i])...)
+	}
+
+	return result
+}
+
+// read imports and reads the resource identified by identity, the same way
+// an `import` block would.
+func (l *sdkListResource) read(ctx context.Context, identity []string) (*schema.ResourceData, error) {
+	d := l.resource.Data(nil)
+	if err := l.identity.set(d, identity...); err != nil {
+		return nil, err
+	}
+
+	imported, err := l.resource.Importer.StateContext(ctx, d, *l.clients)
+	if err != nil {
+		return nil, err
+	}
+	if len(imported) != 1 {
+		return nil, fmt.Errorf("expected a single imported resource, got %d", len(imported))
+	}
+	d = imported[0]
+
+	read := l.resource.ReadWithoutTimeout
+	if read == nil {
+		read = l.resource.ReadContext
+	}
+	for _, readDiag := range read(ctx, d, *l.clients) {
+		if readDiag.Severity == diag.Error {
+			return nil, fmt.Errorf("%s: %s", readDiag.Summary, readDiag.Detail)
+		}
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("resource no longer exists")
+	}
+
+	return d, nil
+}
+
+// setResource converts the SDK state of d into the resource of result.
+func (l *sdkListResource) setResource(ctx context.Context, result *list.ListResult, d *schema.ResourceData) error {
+	state, err := d.State().AttrsAsObjectValue(l.resource.CoreConfigSchema().ImpliedType())
+	if err != nil {
+		return err
+	}
+
+	if attributes, ok := privateStateAttributes["bitbucket"+l.typeName]; ok {
+		values := state.AsValueMap()
+		for _, attribute := range attributes {
+			delete(values, attribute)
+		}
+		state = cty.ObjectVal(values)
+	}
+
+	stateJSON, err := ctyjson.Marshal(state, state.Type())
+	if err != nil {
+		return err
+	}
+
+	raw, err := (&tfprotov5.DynamicValue{JSON: stateJSON}).Unmarshal(result.Resource.Schema.Type().TerraformType(ctx))
+	if err != nil {
+		return err
+	}
+
+	result.Resource.Raw = raw
+	return nil
+}
+
+// listPaginatedValues lists every value of a paginated Bitbucket API
+// endpoint, following the next links until the last page.
+func listPaginatedValues[T any](client *Client, endpoint string) ([]T, error) {
+	return listPaginatedValuesUpTo[T](client, endpoint, 0)
+}
+
+// listPaginatedValuesUpTo lists the first limit values of a paginated
+// Bitbucket API endpoint, or every value when limit is 0.
+func listPaginatedValuesUpTo[T any](client *Client, endpoint string, limit int) ([]T, error) {
+	var values []T
+
+	for endpoint != "" && (limit == 0 || len(values) < limit) {
+		res, err := client.Get(endpoint)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Values []T    `json:"values"`
+			Next   string `json:"next,omitempty"`
+		}
+
+		err = json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, page.Values...)
+		endpoint = strings.TrimPrefix(page.Next, BitbucketEndpoint)
+	}
+
+	if limit > 0 && len(values) > limit {
+		values = values[:limit]
+	}
+
+	return values, nil
+}
@@ -0,0 +1,141 @@
+package bitbucket
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+type listResourceTestTransport map[string]string
+
+func (t listResourceTestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := t[req.URL.RequestURI()]
+	if !ok {
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Body:       io.NopCloser(strings.NewReader(`{"error": {"message": "not found"}}`)),
+			Request:    req,
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestListResource_hook(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = listResourceTestTransport{
+		"/2.0/repositories/ws/repo/hooks?pagelen=100":        `{"values": [{"uuid": "{hook-1}", "description": "first", "url": "https://example.com/1"}], "next": "https://api.bitbucket.org/2.0/repositories/ws/repo/hooks?pagelen=100&page=2"}`,
+		"/2.0/repositories/ws/repo/hooks?pagelen=100&page=2": `{"values": [{"uuid": "{hook-2}", "description": "second", "url": "https://example.com/2"}]}`,
+		"/2.0/repositories/ws/repo/hooks/%7Bhook-1%7D":       `{"uuid": "{hook-1}", "description": "first", "url": "https://example.com/1", "active": true, "events": ["repo:push"]}`,
+		"/2.0/repositories/ws/repo/hooks/%7Bhook-2%7D":       `{"uuid": "{hook-2}", "description": "second", "url": "https://example.com/2", "events": ["pullrequest:created"]}`,
+	}
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	ctx := context.Background()
+
+	server, err := testAccProtoV5ProviderFactories["bitbucket"]()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	providerType := schemaResp.Provider.ValueType()
+	providerConfig, err := tfprotov5.NewDynamicValue(providerType, tftypes.NewValue(providerType, map[string]tftypes.Value{
+		"username":            tftypes.NewValue(tftypes.String, "user"),
+		"password":            tftypes.NewValue(tftypes.String, "password"),
+		"oauth_client_id":     tftypes.NewValue(tftypes.String, nil),
+		"oauth_client_secret": tftypes.NewValue(tftypes.String, nil),
+		"oauth_token":         tftypes.NewValue(tftypes.String, nil),
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	configureResp, err := server.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &providerConfig})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range configureResp.Diagnostics {
+		t.Fatalf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+	}
+
+	listType := schemaResp.ListResourceSchemas["bitbucket_hook"].ValueType()
+	listConfig, err := tfprotov5.NewDynamicValue(listType, tftypes.NewValue(listType, map[string]tftypes.Value{
+		"workspace": tftypes.NewValue(tftypes.String, "ws"),
+		"repo_slug": tftypes.NewValue(tftypes.String, "repo"),
+	}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	stream, err := server.(tfprotov5.ListResourceServer).ListResource(ctx, &tfprotov5.ListResourceRequest{
+		TypeName:        "bitbucket_hook",
+		Config:          &listConfig,
+		IncludeResource: true,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	identityType := hookIdentityProtoType(ctx)
+	resourceType := schemaResp.ResourceSchemas["bitbucket_hook"].ValueType()
+
+	var uuids []string
+	for result := range stream.Results {
+		for _, d := range result.Diagnostics {
+			t.Fatalf("unexpected diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+
+		identity, err := result.Identity.IdentityData.Unmarshal(identityType)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var identityValues map[string]tftypes.Value
+		if err := identity.As(&identityValues); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var uuid string
+		if err := identityValues["uuid"].As(&uuid); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		uuids = append(uuids, uuid)
+
+		resource, err := result.Resource.Unmarshal(resourceType)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var resourceValues map[string]tftypes.Value
+		if err := resource.As(&resourceValues); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		var owner string
+		if err := resourceValues["owner"].As(&owner); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if owner != "ws" {
+			t.Errorf("expected owner ws, got %q", owner)
+		}
+	}
+
+	if got := strings.Join(uuids, ","); got != "{hook-1},{hook-2}" {
+		t.Errorf("expected hooks {hook-1},{hook-2}, got %s", got)
+	}
+}
+
+func hookIdentityProtoType(ctx context.Context) tftypes.Type {
+	return resourceHook().ProtoIdentitySchema(ctx)().ValueType()
+}
@@ -0,0 +1,36 @@
+package bitbucket
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/list"
+)
+
+func newWorkspaceVariableListResource() list.ListResource {
+	return &sdkListResource{
+		typeName:   "_workspace_variable",
+		resource:   resourceWorkspaceVariable(),
+		identity:   workspaceVariableIdentity,
+		attributes: []string{"workspace"},
+		list: func(client *Client, config map[string]string) ([]listedResource, error) {
+			workspace := config["workspace"]
+
+			variables, err := listPaginatedValues[listedPipelineVariable](client, fmt.Sprintf("2.0/workspaces/%s/pipelines-config/variables?pagelen=100",
+				url.PathEscape(workspace),
+			))
+			if err != nil {
+				return nil, err
+			}
+
+			listed := make([]listedResource, 0, len(variables))
+			for _, variable := range variables {
+				listed = append(listed, listedResource{
+					identity:    []string{workspace, variable.UUID},
+					displayName: variable.Key,
+				})
+			}
+			return listed, nil
+		},
+	}
+}
@@ -0,0 +1,402 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// privateStateAttributes lists, by resource type, the computed string
+// attributes that are kept in the private state of a resource rather than in
+// its state. The SDK has no private state API for resources, so these stay in
+// the SDK schema and privateStateServer moves them between the state and the
+// private state on their way to and from Terraform.
+var privateStateAttributes = map[string][]string{
+	"bitbucket_repository_variable": {"value_hash"},
+	"bitbucket_workspace_variable":  {"value_hash"},
+	"bitbucket_deployment_variable": {"value_hash"},
+}
+
+// privateStateServer serves the SDK provider with the attributes listed in
+// privateStateAttributes hidden from Terraform.
+type privateStateServer struct {
+	tfprotov5.ProviderServer
+
+	resources map[string]privateStateResource
+}
+
+// privateStateResource holds the schema of a resource as the SDK sees it and
+// as Terraform sees it, without its private attributes.
+type privateStateResource struct {
+	attributes []string
+	sdkType    tftypes.Type
+	schema     *tfprotov5.Schema
+}
+
+func newPrivateStateServer(p *schema.Provider) func() tfprotov5.ProviderServer {
+	resources := make(map[string]privateStateResource, len(privateStateAttributes))
+	for typeName, attributes := range privateStateAttributes {
+		sdkSchema := p.ResourcesMap[typeName].ProtoSchema(context.Background())()
+		resources[typeName] = privateStateResource{
+			attributes: attributes,
+			sdkType:    sdkSchema.ValueType(),
+			schema:     withoutAttributes(sdkSchema, attributes),
+		}
+	}
+
+	return func() tfprotov5.ProviderServer {
+		return &privateStateServer{
+			ProviderServer: p.GRPCProvider(),
+			resources:      resources,
+		}
+	}
+}
+
+// withoutAttributes returns a copy of s without the given top-level attributes.
+func withoutAttributes(s *tfprotov5.Schema, attributes []string) *tfprotov5.Schema {
+	block := *s.Block
+	block.Attributes = slices.DeleteFunc(slices.Clone(s.Block.Attributes), func(attribute *tfprotov5.SchemaAttribute) bool {
+		return slices.Contains(attributes, attribute.Name)
+	})
+
+	return &tfprotov5.Schema{
+		Version: s.Version,
+		Block:   &block,
+	}
+}
+
+func (s *privateStateServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.ProviderServer.GetProviderSchema(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	for typeName, resource := range s.resources {
+		if _, ok := resp.ResourceSchemas[typeName]; ok {
+			resp.ResourceSchemas[typeName] = resource.schema
+		}
+	}
+
+	return resp, nil
+}
+
+func (s *privateStateServer) ValidateResourceTypeConfig(ctx context.Context, req *tfprotov5.ValidateResourceTypeConfigRequest) (*tfprotov5.ValidateResourceTypeConfigResponse, error) {
+	resource, ok := s.resources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.ValidateResourceTypeConfig(ctx, req)
+	}
+
+	config, err := resource.toSDK(req.Config, nil, nil)
+	if err != nil {
+		return &tfprotov5.ValidateResourceTypeConfigResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+
+	sdkReq := *req
+	sdkReq.Config = config
+	return s.ProviderServer.ValidateResourceTypeConfig(ctx, &sdkReq)
+}
+
+func (s *privateStateServer) UpgradeResourceState(ctx context.Context, req *tfprotov5.UpgradeResourceStateRequest) (*tfprotov5.UpgradeResourceStateResponse, error) {
+	resp, err := s.ProviderServer.UpgradeResourceState(ctx, req)
+	resource, ok := s.resources[req.TypeName]
+	if err != nil || resp == nil || !ok {
+		return resp, err
+	}
+
+	// The private state is not part of an upgrade, so values written by
+	// earlier versions to the state itself are dropped here.
+	resp.UpgradedState, _, err = resource.fromSDK(resp.UpgradedState)
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+	}
+
+	return resp, nil
+}
+
+func (s *privateStateServer) ReadResource(ctx context.Context, req *tfprotov5.ReadResourceRequest) (*tfprotov5.ReadResourceResponse, error) {
+	resource, ok := s.resources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.ReadResource(ctx, req)
+	}
+
+	sdkPrivate, values, err := resource.splitPrivate(req.Private)
+	if err != nil {
+		return &tfprotov5.ReadResourceResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+
+	sdkReq := *req
+	sdkReq.Private = sdkPrivate
+	if sdkReq.CurrentState, err = resource.toSDK(req.CurrentState, values, nil); err != nil {
+		return &tfprotov5.ReadResourceResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+
+	resp, err := s.ProviderServer.ReadResource(ctx, &sdkReq)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	if resp.NewState, values, err = resource.fromSDK(resp.NewState); err == nil {
+		resp.Private, err = mergePrivate(resp.Private, values)
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+	}
+
+	return resp, nil
+}
+
+func (s *privateStateServer) PlanResourceChange(ctx context.Context, req *tfprotov5.PlanResourceChangeRequest) (*tfprotov5.PlanResourceChangeResponse, error) {
+	resource, ok := s.resources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.PlanResourceChange(ctx, req)
+	}
+
+	sdkPrivate, values, err := resource.splitPrivate(req.PriorPrivate)
+	if err != nil {
+		return &tfprotov5.PlanResourceChangeResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+
+	sdkReq := *req
+	sdkReq.PriorPrivate = sdkPrivate
+	if sdkReq.PriorState, err = resource.toSDK(req.PriorState, values, nil); err == nil {
+		if sdkReq.ProposedNewState, err = resource.toSDK(req.ProposedNewState, values, nil); err == nil {
+			sdkReq.Config, err = resource.toSDK(req.Config, nil, nil)
+		}
+	}
+	if err != nil {
+		return &tfprotov5.PlanResourceChangeResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+
+	resp, err := s.ProviderServer.PlanResourceChange(ctx, &sdkReq)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	if resp.PlannedState, values, err = resource.fromSDK(resp.PlannedState); err == nil {
+		resp.PlannedPrivate, err = mergePrivate(resp.PlannedPrivate, values)
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+	}
+
+	return resp, nil
+}
+
+func (s *privateStateServer) ApplyResourceChange(ctx context.Context, req *tfprotov5.ApplyResourceChangeRequest) (*tfprotov5.ApplyResourceChangeResponse, error) {
+	resource, ok := s.resources[req.TypeName]
+	if !ok {
+		return s.ProviderServer.ApplyResourceChange(ctx, req)
+	}
+
+	sdkPrivate, values, err := resource.splitPrivate(req.PlannedPrivate)
+	if err != nil {
+		return &tfprotov5.ApplyResourceChangeResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+
+	// Planned values missing from the planned private state were unknown.
+	sdkReq := *req
+	sdkReq.PlannedPrivate = sdkPrivate
+	if sdkReq.PriorState, err = resource.toSDK(req.PriorState, values, nil); err == nil {
+		if sdkReq.PlannedState, err = resource.toSDK(req.PlannedState, values, tftypes.UnknownValue); err == nil {
+			sdkReq.Config, err = resource.toSDK(req.Config, nil, nil)
+		}
+	}
+	if err != nil {
+		return &tfprotov5.ApplyResourceChangeResponse{Diagnostics: privateStateDiagnostics(err)}, nil
+	}
+
+	resp, err := s.ProviderServer.ApplyResourceChange(ctx, &sdkReq)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	if resp.NewState, values, err = resource.fromSDK(resp.NewState); err == nil {
+		resp.Private, err = mergePrivate(resp.Private, values)
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+	}
+
+	return resp, nil
+}
+
+func (s *privateStateServer) ImportResourceState(ctx context.Context, req *tfprotov5.ImportResourceStateRequest) (*tfprotov5.ImportResourceStateResponse, error) {
+	resp, err := s.ProviderServer.ImportResourceState(ctx, req)
+	if err != nil || resp == nil {
+		return resp, err
+	}
+
+	for _, imported := range resp.ImportedResources {
+		resource, ok := s.resources[imported.TypeName]
+		if !ok {
+			continue
+		}
+
+		var values map[string]*string
+		if imported.State, values, err = resource.fromSDK(imported.State); err == nil {
+			imported.Private, err = mergePrivate(imported.Private, values)
+		}
+		if err != nil {
+			resp.Diagnostics = append(resp.Diagnostics, privateStateDiagnostics(err)...)
+		}
+	}
+
+	return resp, nil
+}
+
+// splitPrivate separates the private values of the resource from the private
+// state the SDK keeps for itself.
+func (r privateStateResource) splitPrivate(private []byte) ([]byte, map[string]*string, error) {
+	if len(private) == 0 {
+		return private, nil, nil
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(private, &all); err != nil {
+		return nil, nil, err
+	}
+
+	values := make(map[string]*string, len(r.attributes))
+	for _, attribute := range r.attributes {
+		raw, ok := all[attribute]
+		if !ok {
+			continue
+		}
+
+		var value *string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, nil, err
+		}
+		values[attribute] = value
+		delete(all, attribute)
+	}
+
+	if len(all) == 0 {
+		return nil, values, nil
+	}
+
+	sdkPrivate, err := json.Marshal(all)
+	return sdkPrivate, values, err
+}
+
+// mergePrivate adds values to the private state returned by the SDK.
+func mergePrivate(sdkPrivate []byte, values map[string]*string) ([]byte, error) {
+	if len(values) == 0 {
+		return sdkPrivate, nil
+	}
+
+	all := make(map[string]interface{})
+	if len(sdkPrivate) > 0 {
+		if err := json.Unmarshal(sdkPrivate, &all); err != nil {
+			return nil, err
+		}
+	}
+
+	for attribute, value := range values {
+		all[attribute] = value
+	}
+
+	return json.Marshal(all)
+}
+
+// toSDK adds the private attributes to a state or config value sent by
+// Terraform, using values where set and missing otherwise.
+func (r privateStateResource) toSDK(dv *tfprotov5.DynamicValue, values map[string]*string, missing interface{}) (*tfprotov5.DynamicValue, error) {
+	if dv == nil {
+		return nil, nil
+	}
+
+	value, err := dv.Unmarshal(r.schema.ValueType())
+	if err != nil {
+		return nil, err
+	}
+
+	if value.IsNull() || !value.IsKnown() {
+		return privateStateDynamicValue(r.sdkType, value)
+	}
+
+	attributes := map[string]tftypes.Value{}
+	if err := value.As(&attributes); err != nil {
+		return nil, err
+	}
+
+	for _, attribute := range r.attributes {
+		if v, ok := values[attribute]; ok {
+			attributes[attribute] = tftypes.NewValue(tftypes.String, v)
+		} else {
+			attributes[attribute] = tftypes.NewValue(tftypes.String, missing)
+		}
+	}
+
+	sdkValue := tftypes.NewValue(r.sdkType, attributes)
+	dynamicValue, err := tfprotov5.NewDynamicValue(r.sdkType, sdkValue)
+	return &dynamicValue, err
+}
+
+// fromSDK removes the private attributes from a state value returned by the
+// SDK, and returns their known values.
+func (r privateStateResource) fromSDK(dv *tfprotov5.DynamicValue) (*tfprotov5.DynamicValue, map[string]*string, error) {
+	if dv == nil {
+		return nil, nil, nil
+	}
+
+	value, err := dv.Unmarshal(r.sdkType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if value.IsNull() || !value.IsKnown() {
+		dynamicValue, err := privateStateDynamicValue(r.schema.ValueType(), value)
+		return dynamicValue, nil, err
+	}
+
+	attributes := map[string]tftypes.Value{}
+	if err := value.As(&attributes); err != nil {
+		return nil, nil, err
+	}
+
+	values := make(map[string]*string, len(r.attributes))
+	for _, attribute := range r.attributes {
+		v := attributes[attribute]
+		delete(attributes, attribute)
+
+		if !v.IsKnown() {
+			continue
+		}
+
+		var s *string
+		if err := v.As(&s); err != nil {
+			return nil, nil, err
+		}
+		values[attribute] = s
+	}
+
+	publicValue := tftypes.NewValue(r.schema.ValueType(), attributes)
+	dynamicValue, err := tfprotov5.NewDynamicValue(r.schema.ValueType(), publicValue)
+	return &dynamicValue, values, err
+}
+
+// privateStateDynamicValue converts a null or unknown object value to typ.
+func privateStateDynamicValue(typ tftypes.Type, value tftypes.Value) (*tfprotov5.DynamicValue, error) {
+	var v interface{}
+	if !value.IsKnown() {
+		v = tftypes.UnknownValue
+	}
+
+	dynamicValue, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, v))
+	return &dynamicValue, err
+}
+
+func privateStateDiagnostics(err error) []*tfprotov5.Diagnostic {
+	return []*tfprotov5.Diagnostic{
+		{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Error converting private state",
+			Detail:   err.Error(),
+		},
+	}
+}
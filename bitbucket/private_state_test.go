@@ -0,0 +1,117 @@
+package bitbucket
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestPrivateStateServer_schema(t *testing.T) {
+	server, err := testAccProtoV5ProviderFactories["bitbucket"]()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for typeName, attributes := range privateStateAttributes {
+		resourceSchema, ok := resp.ResourceSchemas[typeName]
+		if !ok {
+			t.Errorf("expected %s resource schema", typeName)
+			continue
+		}
+
+		for _, attribute := range resourceSchema.Block.Attributes {
+			for _, private := range attributes {
+				if attribute.Name == private {
+					t.Errorf("expected %s.%s to be hidden from the schema", typeName, private)
+				}
+			}
+		}
+	}
+}
+
+func TestPrivateStateResource_roundTrip(t *testing.T) {
+	resource := newPrivateStateServer(Provider())().(*privateStateServer).resources["bitbucket_repository_variable"]
+
+	sdkState := func(valueHash tftypes.Value) tftypes.Value {
+		attributes := map[string]tftypes.Value{}
+		for name, typ := range resource.sdkType.(tftypes.Object).AttributeTypes {
+			attributes[name] = tftypes.NewValue(typ, nil)
+		}
+		attributes["id"] = tftypes.NewValue(tftypes.String, "test")
+		attributes["value_hash"] = valueHash
+		return tftypes.NewValue(resource.sdkType, attributes)
+	}
+
+	state := sdkState(tftypes.NewValue(tftypes.String, "hash"))
+	dv, err := tfprotov5.NewDynamicValue(resource.sdkType, state)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	public, values, err := resource.fromSDK(&dv)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := public.Unmarshal(resource.schema.ValueType()); err != nil {
+		t.Fatalf("expected a state without value_hash, got: %s", err)
+	}
+
+	private, err := mergePrivate([]byte(`{"schema_version":"0"}`), values)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	sdkPrivate, values, err := resource.splitPrivate(private)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if string(sdkPrivate) != `{"schema_version":"0"}` {
+		t.Errorf("expected the SDK private state to be kept, got %s", sdkPrivate)
+	}
+
+	restored, err := resource.toSDK(public, values, nil)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	restoredState, err := restored.Unmarshal(resource.sdkType)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !restoredState.Equal(state) {
+		t.Errorf("expected %s, got %s", state, restoredState)
+	}
+
+	planned, err := resource.toSDK(public, nil, tftypes.UnknownValue)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	plannedState, err := planned.Unmarshal(resource.sdkType)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if expected := sdkState(tftypes.NewValue(tftypes.String, tftypes.UnknownValue)); !plannedState.Equal(expected) {
+		t.Errorf("expected %s, got %s", expected, plannedState)
+	}
+
+	_, values, err = resource.fromSDK(planned)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, ok := values["value_hash"]; ok {
+		t.Errorf("expected an unknown value_hash not to be kept in private state")
+	}
+}
@@ -18,8 +18,9 @@ type ProviderConfig struct {
 }
 
 type Clients struct {
-	genClient  ProviderConfig
-	httpClient Client
+	genClient   ProviderConfig
+	httpClient  Client
+	oauthConfig *oauth2clientcreds.Config
 }
 
 // Provider will create the necessary terraform provider to talk to the
@@ -68,9 +69,11 @@ func Provider() *schema.Provider {
 		},
 		ConfigureFunc: providerConfigure,
 		ResourcesMap: map[string]*schema.Resource{
+			"bitbucket_branch":                      resourceBranch(),
 			"bitbucket_branch_restriction":          resourceBranchRestriction(),
 			"bitbucket_branching_model":             resourceBranchingModel(),
 			"bitbucket_commit_file":                 resourceCommitFile(),
+			"bitbucket_commit_files":                resourceCommitFiles(),
 			"bitbucket_default_reviewers":           resourceDefaultReviewers(),
 			"bitbucket_deploy_key":                  resourceDeployKey(),
 			"bitbucket_deployment":                  resourceDeployment(),
@@ -90,12 +93,17 @@ func Provider() *schema.Provider {
 			"bitbucket_repository_user_permission":  resourceRepositoryUserPermission(),
 			"bitbucket_repository_variable":         resourceRepositoryVariable(),
 			"bitbucket_ssh_key":                     resourceSshKey(),
+			"bitbucket_tag":                         resourceTag(),
 			"bitbucket_workspace_hook":              resourceWorkspaceHook(),
 			"bitbucket_workspace_variable":          resourceWorkspaceVariable(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
+			"bitbucket_commit":                    dataCommit(),
+			"bitbucket_commits":                   dataCommits(),
 			"bitbucket_current_user":              dataCurrentUser(),
 			"bitbucket_deployment":                dataDeployment(),
+			"bitbucket_directory":                 dataDirectory(),
+			"bitbucket_file":                      dataFile(),
 			"bitbucket_group":                     dataGroup(),
 			"bitbucket_group_members":             dataGroupMembers(),
 			"bitbucket_groups":                    dataGroups(),
@@ -103,6 +111,9 @@ func Provider() *schema.Provider {
 			"bitbucket_ip_ranges":                 dataIPRanges(),
 			"bitbucket_pipeline_oidc_config":      dataPipelineOidcConfig(),
 			"bitbucket_pipeline_oidc_config_keys": dataPipelineOidcConfigKeys(),
+			"bitbucket_repository":                dataRepository(),
+			"bitbucket_repositories":              dataRepositories(),
+			"bitbucket_tags":                      dataTags(),
 			"bitbucket_user":                      dataUser(),
 			"bitbucket_workspace":                 dataWorkspace(),
 			"bitbucket_workspace_members":         dataWorkspaceMembers(),
@@ -111,21 +122,50 @@ func Provider() *schema.Provider {
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
+	return newClients(providerSettings{
+		Username:          d.Get("username").(string),
+		Password:          d.Get("password").(string),
+		OAuthClientID:     d.Get("oauth_client_id").(string),
+		OAuthClientSecret: d.Get("oauth_client_secret").(string),
+		OAuthToken:        d.Get("oauth_token").(string),
+	})
+}
+
+// providerSettings holds the provider configuration shared by the SDK and
+// framework halves of the provider, after environment defaults are applied.
+type providerSettings struct {
+	Username          string
+	Password          string
+	OAuthClientID     string
+	OAuthClientSecret string
+	OAuthToken        string
+	// HTTPClient sends the requests of both API clients, a new client by
+	// default.
+	HTTPClient *http.Client
+}
+
+func newClients(settings providerSettings) (Clients, error) {
 	authCtx := context.Background()
 
+	httpClient := settings.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
 	client := &Client{
-		HTTPClient: &http.Client{},
+		HTTPClient: httpClient,
 	}
 
-	if username, ok := d.GetOk("username"); ok {
-		var password interface{}
-		if password, ok = d.GetOk("password"); !ok {
-			return nil, fmt.Errorf("found username for basic auth, but password not specified")
+	var oauthConfig *oauth2clientcreds.Config
+
+	if settings.Username != "" {
+		if settings.Password == "" {
+			return Clients{}, fmt.Errorf("found username for basic auth, but password not specified")
 		}
 		log.Printf("[DEBUG] Using API Basic Auth")
 
-		user := username.(string)
-		pass := password.(string)
+		user := settings.Username
+		pass := settings.Password
 
 		cred := bitbucket.BasicAuth{
 			UserName: user,
@@ -136,39 +176,40 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		client.Password = &pass
 	}
 
-	if v, ok := d.GetOk("oauth_token"); ok && v.(string) != "" {
-		token := v.(string)
+	if settings.OAuthToken != "" {
+		token := settings.OAuthToken
 		client.OAuthToken = &token
 		authCtx = context.WithValue(authCtx, bitbucket.ContextAccessToken, token)
 	}
 
-	if clientID, ok := d.GetOk("oauth_client_id"); ok {
-		clientSecret, ok := d.GetOk("oauth_client_secret")
-		if !ok {
-			return nil, fmt.Errorf("found client ID for OAuth via Client Credentials Grant, but client secret was not specified")
+	if settings.OAuthClientID != "" {
+		if settings.OAuthClientSecret == "" {
+			return Clients{}, fmt.Errorf("found client ID for OAuth via Client Credentials Grant, but client secret was not specified")
 		}
 
-		config := &oauth2clientcreds.Config{
-			ClientID:     clientID.(string),
-			ClientSecret: clientSecret.(string),
+		oauthConfig = &oauth2clientcreds.Config{
+			ClientID:     settings.OAuthClientID,
+			ClientSecret: settings.OAuthClientSecret,
 			TokenURL:     oauth2bitbucket.Endpoint.TokenURL,
 		}
 
-		tokenSource := config.TokenSource(authCtx)
+		tokenSource := oauthConfig.TokenSource(authCtx)
 
 		client.OAuthTokenSource = tokenSource
 		authCtx = context.WithValue(authCtx, bitbucket.ContextOAuth2, tokenSource)
 	}
 
 	conf := bitbucket.NewConfiguration()
+	conf.HTTPClient = httpClient
 	apiClient := ProviderConfig{
 		ApiClient:   bitbucket.NewAPIClient(conf),
 		AuthContext: authCtx,
 	}
 
 	clients := Clients{
-		genClient:  apiClient,
-		httpClient: *client,
+		genClient:   apiClient,
+		httpClient:  *client,
+		oauthConfig: oauthConfig,
 	}
 
 	return clients, nil
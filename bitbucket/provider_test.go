@@ -1,15 +1,30 @@
 package bitbucket
 
 import (
+	"context"
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 var testAccProviders map[string]*schema.Provider
 var testAccProvider *schema.Provider
 
+// testAccProtoV5ProviderFactories serves the muxed provider, for tests that
+// need the framework half such as ephemeral resources.
+var testAccProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"bitbucket": func() (tfprotov5.ProviderServer, error) {
+		serverFactory, err := ProtoV5ProviderServerFactory(context.Background())
+		if err != nil {
+			return nil, err
+		}
+
+		return serverFactory(), nil
+	},
+}
+
 func init() {
 	testAccProvider = Provider()
 	testAccProviders = map[string]*schema.Provider{
@@ -27,6 +42,34 @@ func TestProvider_impl(t *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestProvider_muxServer(t *testing.T) {
+	server, err := testAccProtoV5ProviderFactories["bitbucket"]()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resp, err := server.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, d := range resp.Diagnostics {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Fatalf("unexpected error diagnostic: %s: %s", d.Summary, d.Detail)
+		}
+	}
+
+	if _, ok := resp.EphemeralResourceSchemas["bitbucket_oauth_access_token"]; !ok {
+		t.Errorf("expected bitbucket_oauth_access_token ephemeral resource schema")
+	}
+
+	for _, name := range []string{"bitbucket_repository", "bitbucket_hook", "bitbucket_branch_restriction", "bitbucket_repository_variable", "bitbucket_workspace_variable", "bitbucket_deployment_variable"} {
+		if _, ok := resp.ListResourceSchemas[name]; !ok {
+			t.Errorf("expected %s list resource schema", name)
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv("BITBUCKET_USERNAME"); v == "" {
 		t.Fatal("BITBUCKET_USERNAME must be set for acceptence tests")
@@ -46,3 +89,19 @@ func testAccPreCheckPipeSchedule(t *testing.T) {
 		t.Fatal("BITBUCKET_PIPELINED_REPO must be set for acceptence tests")
 	}
 }
+
+func testAccPreCheckOAuthClient(t *testing.T) {
+	if v := os.Getenv("BITBUCKET_OAUTH_CLIENT_ID"); v == "" {
+		t.Fatal("BITBUCKET_OAUTH_CLIENT_ID must be set for acceptence tests")
+	}
+
+	if v := os.Getenv("BITBUCKET_OAUTH_CLIENT_SECRET"); v == "" {
+		t.Fatal("BITBUCKET_OAUTH_CLIENT_SECRET must be set for acceptence tests")
+	}
+}
+
+func testAccPreCheckTransferWorkspace(t *testing.T) {
+	if v := os.Getenv("BITBUCKET_TRANSFER_TEAM"); v == "" {
+		t.Fatal("BITBUCKET_TRANSFER_TEAM must be set for acceptence tests")
+	}
+}
@@ -0,0 +1,208 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+)
+
+// BitbucketGitEndpoint is the base URL of the Git repositories of Bitbucket.
+const BitbucketGitEndpoint = "https://bitbucket.org/"
+
+// repositoryImport seeds a new repository with the history of a remote Git
+// repository. Bitbucket Cloud has no import API, so the source is cloned with
+// git on the machine running Terraform and pushed to the new repository.
+type repositoryImport struct {
+	url      string
+	username string
+	password string
+	mirror   bool
+}
+
+func expandRepositoryImport(v []interface{}) *repositoryImport {
+	tfMap := v[0].(map[string]interface{})
+
+	return &repositoryImport{
+		url:      tfMap["url"].(string),
+		username: tfMap["username"].(string),
+		password: tfMap["password"].(string),
+		mirror:   tfMap["mirror"].(bool),
+	}
+}
+
+// run imports the source into the repository workspace/repoSlug and waits
+// until Bitbucket serves its main branch. When mainbranch is empty, the main
+// branch of the repository becomes the default branch of the source.
+func (ri *repositoryImport) run(ctx context.Context, client *Client, workspace, repoSlug, mainbranch string) error {
+	if err := checkGitInstalled("import_source"); err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-provider-bitbucket-import-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ri.clone(ctx, dir); err != nil {
+		return err
+	}
+
+	head, err := runGit(ctx, dir, nil, "symbolic-ref", "--short", "HEAD")
+	if err != nil {
+		return err
+	}
+
+	if _, err := runGit(ctx, dir, nil, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		log.Printf("[DEBUG] Source repository %s is empty, nothing to import", ri.redactedURL())
+		return nil
+	}
+
+	branch := mainbranch
+	if branch == "" {
+		branch = head
+	}
+
+	hash, err := runGit(ctx, dir, nil, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch)
+	if err != nil {
+		return fmt.Errorf("the main branch %s is not a branch of the source", branch)
+	}
+
+	authorization, err := client.gitAuthorization()
+	if err != nil {
+		return err
+	}
+
+	target := fmt.Sprintf("%s%s/%s.git", BitbucketGitEndpoint, workspace, repoSlug)
+	if err := ri.push(ctx, dir, target, authorization); err != nil {
+		return err
+	}
+
+	if err := waitForRepositoryBranch(ctx, client, workspace, repoSlug, branch, hash); err != nil {
+		return err
+	}
+
+	if mainbranch != "" {
+		return nil
+	}
+
+	return setRepositoryMainbranch(client, workspace, repoSlug, head)
+}
+
+// clone clones the source into dir as a bare repository. A mirror clone also
+// fetches every other ref of the source, such as notes and pull requests.
+func (ri *repositoryImport) clone(ctx context.Context, dir string) error {
+	mode := "--bare"
+	if ri.mirror {
+		mode = "--mirror"
+	}
+
+	var authorization string
+	if ri.username != "" || ri.password != "" {
+		authorization = basicAuthorization(ri.username, ri.password)
+	}
+
+	_, err := runGit(ctx, "", gitAuthorizationEnv(ri.url, authorization), "clone", "--quiet", mode, "--", ri.url, dir)
+	return err
+}
+
+// push pushes the clone in dir to target. Unless mirroring, only branches and
+// tags are pushed.
+func (ri *repositoryImport) push(ctx context.Context, dir, target, authorization string) error {
+	args := []string{"push", "--quiet"}
+	if ri.mirror {
+		args = append(args, "--mirror", target)
+	} else {
+		args = append(args, target, "refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*")
+	}
+
+	_, err := runGit(ctx, dir, gitAuthorizationEnv(target, authorization), args...)
+	return err
+}
+
+func (ri *repositoryImport) redactedURL() string {
+	u, err := url.Parse(ri.url)
+	if err != nil {
+		return ri.url
+	}
+
+	return u.Redacted()
+}
+
+// waitForRepositoryBranch waits until the API serves branch of a repository
+// at hash, the head pushed by the import, or ctx is done.
+func waitForRepositoryBranch(ctx context.Context, client *Client, workspace, repoSlug, branch, hash string) error {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return errors.New("no deadline to wait for the import")
+	}
+
+	return retry.RetryContext(ctx, time.Until(deadline), func() *retry.RetryError {
+		current, err := getBranchHash(client, workspace, repoSlug, branch)
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+
+		if current != hash {
+			log.Printf("[DEBUG] Waiting for branch %s of Repository (%s/%s) to be at %s, got %q", branch, workspace, repoSlug, hash, current)
+			return retry.RetryableError(fmt.Errorf("branch %s is at %q, expected %s", branch, current, hash))
+		}
+
+		return nil
+	})
+}
+
+// checkGitInstalled returns an error naming feature when the git command,
+// which feature runs on the machine running Terraform, is not on the PATH.
+func checkGitInstalled(feature string) error {
+	if _, err := exec.LookPath("git"); err != nil {
+		return fmt.Errorf("%s requires the git command on the PATH of the machine running Terraform: %w", feature, err)
+	}
+
+	return nil
+}
+
+// runGit runs git in dir and returns its trimmed output. Git never prompts for
+// credentials, so missing credentials fail the command instead of hanging it.
+func runGit(ctx context.Context, dir string, env []string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// gitAuthorizationEnv configures git to send the authorization header to
+// remoteURL only, without the credentials showing up in its arguments.
+func gitAuthorizationEnv(remoteURL, authorization string) []string {
+	if authorization == "" {
+		return nil
+	}
+
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		fmt.Sprintf("GIT_CONFIG_KEY_0=http.%s.extraHeader", remoteURL),
+		"GIT_CONFIG_VALUE_0=Authorization: " + authorization,
+	}
+}
+
+func basicAuthorization(username, password string) string {
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+}
@@ -0,0 +1,256 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+var branchIdentity = newResourceIdentity("/", "workspace", "repo_slug", "name")
+
+func resourceBranch() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceBranchCreate,
+		ReadWithoutTimeout:   resourceBranchRead,
+		UpdateWithoutTimeout: resourceBranchUpdate,
+		DeleteWithoutTimeout: resourceBranchDelete,
+		Importer: branchIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			// Branch names may contain slashes, so prefer the identity when importing by identity.
+			idParts, err := branchIdentity.values(d)
+			if err != nil {
+				idParts = strings.SplitN(d.Id(), "/", 3)
+			}
+			if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/BRANCH", d.Id())
+			}
+			d.SetId(strings.Join(idParts, "/"))
+			d.Set("workspace", idParts[0])
+			d.Set("repo_slug", idParts[1])
+			d.Set("name", idParts[2])
+			d.Set("force_delete", false)
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: branchIdentity.identitySchema(),
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"repo_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringIsNotWhiteSpace,
+					validation.StringDoesNotMatch(regexp.MustCompile(`^refs/`), "must be a branch name, not a ref"),
+				),
+			},
+			"source": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				// The source of an imported branch is unknown.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Id() != "" && old == ""
+				},
+			},
+			"force_delete": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"target_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+type branchBody struct {
+	Name   string           `json:"name"`
+	Target branchBodyTarget `json:"target"`
+}
+
+type branchBodyTarget struct {
+	Hash string `json:"hash"`
+}
+
+// newBranchFromResource returns the request body creating the branch of d at
+// hash, the commit its source resolves to.
+func newBranchFromResource(d *schema.ResourceData, hash string) *branchBody {
+	return &branchBody{
+		Name:   d.Get("name").(string),
+		Target: branchBodyTarget{Hash: hash},
+	}
+}
+
+func resourceBranchCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	name := d.Get("name").(string)
+	source := d.Get("source").(string)
+	id := fmt.Sprintf("%s/%s/%s", workspace, repoSlug, name)
+
+	existing, err := getBranch(&client, workspace, repoSlug, name)
+	if err != nil {
+		return diag.Errorf("error reading Branch (%s): %s", id, err)
+	}
+	if existing != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Branch (%s) already exists", id),
+			Detail:   fmt.Sprintf("To manage the existing branch, import it with: terraform import <address> %s", id),
+		}}
+	}
+
+	hash, err := resolveCommit(m.(Clients), workspace, repoSlug, source)
+	if err != nil {
+		return diag.Errorf("error resolving source %q of Branch (%s): %s", source, id, err)
+	}
+
+	payload, err := json.Marshal(newBranchFromResource(d, hash))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Branch Request: %s", string(payload))
+	_, err = client.Post(fmt.Sprintf("2.0/repositories/%s/%s/refs/branches", url.PathEscape(workspace), url.PathEscape(repoSlug)), bytes.NewBuffer(payload))
+	if err != nil {
+		return diag.Errorf("error creating Branch (%s): %s", id, err)
+	}
+
+	d.SetId(id)
+
+	return resourceBranchRead(ctx, d, m)
+}
+
+func resourceBranchRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, name, err := branchId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	branch, err := getBranch(&client, workspace, repoSlug, name)
+	if err != nil {
+		return diag.Errorf("error reading Branch (%s): %s", d.Id(), err)
+	}
+	if branch == nil {
+		log.Printf("[WARN] Branch (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	d.Set("name", branch.Name)
+	d.Set("target_hash", branch.Target.Hash)
+
+	if err := branchIdentity.set(d, workspace, repoSlug, name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceBranchUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Only force_delete can change in place, and it is only used on destroy.
+	return resourceBranchRead(ctx, d, m)
+}
+
+func resourceBranchDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.Get("force_delete").(bool) {
+		log.Printf("[DEBUG] Branch (%s) is kept in the repository, as force_delete is not set", d.Id())
+		return nil
+	}
+
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, name, err := branchId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.Delete(branchEndpoint(workspace, repoSlug, name))
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("error deleting Branch (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// branchEndpoint returns the endpoint of a branch. Branch names may contain
+// slashes and other characters that must be escaped in the path.
+func branchEndpoint(workspace, repoSlug, name string) string {
+	return fmt.Sprintf("2.0/repositories/%s/%s/refs/branches/%s",
+		url.PathEscape(workspace), url.PathEscape(repoSlug), url.PathEscape(name))
+}
+
+// getBranch returns a branch, or nil when it does not exist.
+func getBranch(client *Client, workspace, repoSlug, name string) (*branchBody, error) {
+	res, err := client.Get(branchEndpoint(workspace, repoSlug, name))
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var branch branchBody
+	if err := json.NewDecoder(res.Body).Decode(&branch); err != nil {
+		return nil, err
+	}
+
+	return &branch, nil
+}
+
+// resolveCommit returns the hash of the commit a branch, tag or commit
+// points to.
+func resolveCommit(clients Clients, workspace, repoSlug, revision string) (string, error) {
+	client := clients.httpClient
+
+	commit, err := getCommit(&client, workspace, repoSlug, revision)
+	if err != nil {
+		return "", err
+	}
+	if commit == nil {
+		return "", fmt.Errorf("no branch, tag or commit %q in %s/%s", revision, workspace, repoSlug)
+	}
+
+	return commit.Hash, nil
+}
+
+func branchId(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, "/", 3)
+
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/BRANCH", id)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
@@ -1,18 +1,21 @@
 package bitbucket
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
-
 	"net/http"
 	"net/url"
+	"regexp"
+	"slices"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/strollby/bitbucket-go-client"
 )
 
 // BranchRestriction is the data we need to send to create a new branch restriction for the repository
@@ -23,39 +26,49 @@ type BranchRestriction struct {
 	BranchType      string  `json:"branch_type,omitempty"`
 	Pattern         string  `json:"pattern,omitempty"`
 	Value           int     `json:"value,omitempty"`
-	Users           []User  `json:"users,omitempty"`
-	Groups          []Group `json:"groups,omitempty"`
+	Users           []User  `json:"users"`
+	Groups          []Group `json:"groups"`
 }
 
-// User is just the user struct we want to use for BranchRestrictions
+// User is just the user struct we want to use for BranchRestrictions. Users
+// are identified by UUID or account ID, as usernames are no longer available.
 type User struct {
-	Username string `json:"username,omitempty"`
+	UUID      string `json:"uuid,omitempty"`
+	AccountID string `json:"account_id,omitempty"`
 }
 
 // Group is the group we want to add to a branch restriction
 type Group struct {
-	Slug  string `json:"slug,omitempty"`
-	Owner User   `json:"owner,omitempty"`
+	Slug      string          `json:"slug,omitempty"`
+	FullSlug  string          `json:"full_slug,omitempty"`
+	Workspace *GroupWorkspace `json:"workspace,omitempty"`
+}
+
+// GroupWorkspace is the workspace a group of a branch restriction belongs to
+type GroupWorkspace struct {
+	Slug string `json:"slug,omitempty"`
 }
 
+var branchRestrictionIdentity = newResourceIdentity("/", "workspace", "repo_slug", "id")
+
 func resourceBranchRestriction() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceBranchRestrictionsCreate,
 		ReadContext:   resourceBranchRestrictionsRead,
 		UpdateContext: resourceBranchRestrictionsUpdate,
 		DeleteContext: resourceBranchRestrictionsDelete,
-		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				idParts := strings.Split(d.Id(), "/")
-				if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected OWNER/REPO/BRANCH-RESTRICTION-ID", d.Id())
-				}
-				d.SetId(idParts[2])
-				d.Set("owner", idParts[0])
-				d.Set("repository", idParts[1])
-				return []*schema.ResourceData{d}, nil
-			},
-		},
+		CustomizeDiff: customizeBranchRestrictionDiff,
+		Importer: branchRestrictionIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			idParts := strings.Split(d.Id(), "/")
+			if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected OWNER/REPO/BRANCH-RESTRICTION-ID", d.Id())
+			}
+			d.SetId(idParts[2])
+			d.Set("owner", idParts[0])
+			d.Set("repository", idParts[1])
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: branchRestrictionIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"owner": {
@@ -106,18 +119,39 @@ func resourceBranchRestriction() *schema.Resource {
 				ValidateFunc: validation.StringInSlice([]string{"feature", "bugfix", "release", "hotfix", "development", "production"}, false),
 			},
 			"users": {
-				Type:     schema.TypeSet,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				Optional: true,
-				Set:      schema.HashString,
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateBranchRestrictionUser,
+				},
+				Optional:         true,
+				Set:              branchRestrictionUserHash,
+				DiffSuppressFunc: suppressEquivalentBranchRestrictionUsers,
+			},
+			"user_accounts": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"account_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
 			},
 			"groups": {
 				Type: schema.TypeSet,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"owner": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "The slug of the workspace of the group",
 						},
 						"slug": {
 							Type:     schema.TypeString,
@@ -125,7 +159,9 @@ func resourceBranchRestriction() *schema.Resource {
 						},
 					},
 				},
-				Optional: true,
+				Optional:         true,
+				Set:              branchRestrictionGroupHash,
+				DiffSuppressFunc: suppressEquivalentBranchRestrictionGroups,
 			},
 
 			"value": {
@@ -136,81 +172,87 @@ func resourceBranchRestriction() *schema.Resource {
 	}
 }
 
-func createBranchRestriction(d *schema.ResourceData) *bitbucket.Branchrestriction {
-
-	users := make([]bitbucket.Account, 0, d.Get("users").(*schema.Set).Len())
+func expandBranchRestriction(d *schema.ResourceData) *BranchRestriction {
+	users := make([]User, 0, d.Get("users").(*schema.Set).Len())
 
 	for _, item := range d.Get("users").(*schema.Set).List() {
-		account := bitbucket.Account{
-			Username: item.(string),
-		}
+		id := normalizeBranchRestrictionUser(item.(string))
 
-		users = append(users, account)
+		if uuidPattern.MatchString(id) {
+			users = append(users, User{UUID: id})
+		} else {
+			users = append(users, User{AccountID: id})
+		}
 	}
 
-	groups := make([]bitbucket.Group, 0, d.Get("groups").(*schema.Set).Len())
+	groups := make([]Group, 0, d.Get("groups").(*schema.Set).Len())
 
 	for _, item := range d.Get("groups").(*schema.Set).List() {
 		m := item.(map[string]interface{})
 
-		account := &bitbucket.Account{
-			Username: m["owner"].(string),
-		}
-
-		group := bitbucket.Group{
-			Owner: account,
-			Slug:  m["slug"].(string),
-		}
-
-		groups = append(groups, group)
+		groups = append(groups, Group{
+			Slug:      m["slug"].(string),
+			FullSlug:  fmt.Sprintf("%s:%s", m["owner"].(string), m["slug"].(string)),
+			Workspace: &GroupWorkspace{Slug: m["owner"].(string)},
+		})
 	}
 
-	restict := &bitbucket.Branchrestriction{
+	restrict := &BranchRestriction{
 		Kind:   d.Get("kind").(string),
-		Value:  int32(d.Get("value").(int)),
+		Value:  d.Get("value").(int),
 		Users:  users,
 		Groups: groups,
 	}
 
 	if v, ok := d.GetOk("pattern"); ok {
-		restict.Pattern = v.(string)
+		restrict.Pattern = v.(string)
 	}
 
 	if v, ok := d.GetOk("branch_type"); ok {
-		restict.BranchType = v.(string)
+		restrict.BranchType = v.(string)
 	}
 
 	if v, ok := d.GetOk("branch_match_kind"); ok {
-		restict.BranchMatchKind = v.(string)
+		restrict.BranchMatchkind = v.(string)
 	}
 
-	return restict
+	return restrict
 }
 
 func resourceBranchRestrictionsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(Clients).genClient
-	brApi := c.ApiClient.BranchRestrictionsApi
-	branchRestriction := createBranchRestriction(d)
+	client := m.(Clients).httpClient
+	branchRestriction := expandBranchRestriction(d)
 
 	repo := d.Get("repository").(string)
 	workspace := d.Get("owner").(string)
-	branchRestrictionReq, _, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsPost(c.AuthContext, *branchRestriction, repo, workspace)
 
-	if err := handleClientError(err); err != nil {
+	bytedata, err := json.Marshal(branchRestriction)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Branch Restriction Request: %s", string(bytedata))
+	res, err := client.Post(fmt.Sprintf("2.0/repositories/%s/%s/branch-restrictions", workspace, repo), bytes.NewBuffer(bytedata))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+
+	var branchRestrictionRes BranchRestriction
+	if err := json.NewDecoder(res.Body).Decode(&branchRestrictionRes); err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(string(fmt.Sprintf("%v", branchRestrictionReq.Id)))
+	d.SetId(string(fmt.Sprintf("%v", branchRestrictionRes.ID)))
 
 	return resourceBranchRestrictionsRead(ctx, d, m)
 }
 
 func resourceBranchRestrictionsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(Clients).genClient
-	brApi := c.ApiClient.BranchRestrictionsApi
+	client := m.(Clients).httpClient
 
-	brRes, res, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdGet(c.AuthContext, url.PathEscape(d.Id()),
-		d.Get("repository").(string), d.Get("owner").(string))
+	res, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s/branch-restrictions/%s",
+		d.Get("owner").(string), d.Get("repository").(string), url.PathEscape(d.Id())))
 
 	if res != nil && res.StatusCode == http.StatusNotFound {
 		log.Printf("[WARN] Branch Restrictions (%s) not found, removing from state", d.Id())
@@ -218,32 +260,48 @@ func resourceBranchRestrictionsRead(ctx context.Context, d *schema.ResourceData,
 		return nil
 	}
 
-	if err := handleClientError(err); err != nil {
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	defer res.Body.Close()
+
+	var brRes BranchRestriction
+	if err := json.NewDecoder(res.Body).Decode(&brRes); err != nil {
 		return diag.FromErr(err)
 	}
 
-	d.SetId(string(fmt.Sprintf("%v", brRes.Id)))
+	log.Printf("[DEBUG] Branch Restriction Response Decoded: %#v", brRes)
+
+	d.SetId(string(fmt.Sprintf("%v", brRes.ID)))
 	d.Set("kind", brRes.Kind)
 	d.Set("pattern", brRes.Pattern)
 	d.Set("value", brRes.Value)
-	d.Set("users", brRes.Users)
-	d.Set("groups", brRes.Groups)
+	d.Set("users", flattenBranchRestrictionUsers(brRes.Users, d.Get("users").(*schema.Set)))
+	d.Set("user_accounts", flattenBranchRestrictionUserAccounts(brRes.Users))
+	d.Set("groups", flattenBranchRestrictionGroups(brRes.Groups, d.Get("groups").(*schema.Set)))
 	d.Set("branch_type", brRes.BranchType)
-	d.Set("branch_match_kind", brRes.BranchMatchKind)
+	d.Set("branch_match_kind", brRes.BranchMatchkind)
+
+	if err := branchRestrictionIdentity.set(d, d.Get("owner").(string), d.Get("repository").(string), d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
 
 func resourceBranchRestrictionsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(Clients).genClient
-	brApi := c.ApiClient.BranchRestrictionsApi
-	branchRestriction := createBranchRestriction(d)
+	client := m.(Clients).httpClient
+	branchRestriction := expandBranchRestriction(d)
 
-	_, _, err := brApi.RepositoriesWorkspaceRepoSlugBranchRestrictionsIdPut(c.AuthContext,
-		*branchRestriction, url.PathEscape(d.Id()),
-		d.Get("repository").(string), d.Get("owner").(string))
+	bytedata, err := json.Marshal(branchRestriction)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-	if err := handleClientError(err); err != nil {
+	log.Printf("[DEBUG] Branch Restriction Request: %s", string(bytedata))
+	_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s/branch-restrictions/%s",
+		d.Get("owner").(string), d.Get("repository").(string), url.PathEscape(d.Id())), bytes.NewBuffer(bytedata))
+	if err != nil {
 		return diag.FromErr(err)
 	}
 
@@ -268,3 +326,211 @@ func resourceBranchRestrictionsDelete(ctx context.Context, d *schema.ResourceDat
 
 	return nil
 }
+
+var (
+	uuidPattern      = regexp.MustCompile(`^\{?[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}\}?$`)
+	accountIdPattern = regexp.MustCompile(`^([0-9]+:[0-9a-fA-F-]{36}|[0-9a-fA-F]{24})$`)
+)
+
+// validateBranchRestrictionUser accepts the UUID or the account ID of a user,
+// pointing configurations still listing usernames to their replacement.
+func validateBranchRestrictionUser(v interface{}, k string) ([]string, []error) {
+	id, ok := v.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+
+	if uuidPattern.MatchString(id) || accountIdPattern.MatchString(id) {
+		return nil, nil
+	}
+
+	return nil, []error{fmt.Errorf("%s: %q is not a UUID or an account ID, usernames are no longer supported, use the UUID or account ID of the user, e.g. from the bitbucket_user data source", k, id)}
+}
+
+// normalizeBranchRestrictionUser returns a UUID in braces and lower case, and
+// an account ID as is.
+func normalizeBranchRestrictionUser(id string) string {
+	if !uuidPattern.MatchString(id) {
+		return id
+	}
+
+	return "{" + strings.ToLower(strings.Trim(id, "{}")) + "}"
+}
+
+func branchRestrictionUserHash(v interface{}) int {
+	return schema.HashString(normalizeBranchRestrictionUser(v.(string)))
+}
+
+// flattenBranchRestrictionUsers identifies each user like in current, by UUID
+// or by account ID, and by UUID when new.
+func flattenBranchRestrictionUsers(users []User, current *schema.Set) []interface{} {
+	known := map[string]string{}
+	for _, v := range current.List() {
+		known[normalizeBranchRestrictionUser(v.(string))] = v.(string)
+	}
+
+	flat := make([]interface{}, 0, len(users))
+	for _, user := range users {
+		switch {
+		case known[normalizeBranchRestrictionUser(user.UUID)] != "":
+			flat = append(flat, known[normalizeBranchRestrictionUser(user.UUID)])
+		case user.AccountID != "" && known[user.AccountID] != "":
+			flat = append(flat, known[user.AccountID])
+		case user.UUID != "":
+			flat = append(flat, user.UUID)
+		default:
+			flat = append(flat, user.AccountID)
+		}
+	}
+
+	return flat
+}
+
+func flattenBranchRestrictionUserAccounts(users []User) []interface{} {
+	flat := make([]interface{}, 0, len(users))
+	for _, user := range users {
+		flat = append(flat, map[string]interface{}{
+			"uuid":       user.UUID,
+			"account_id": user.AccountID,
+		})
+	}
+
+	return flat
+}
+
+// suppressEquivalentBranchRestrictionUsers suppresses the diff of users
+// identified by their UUID instead of their account ID, or the other way
+// around, using the accounts of the users last read.
+func suppressEquivalentBranchRestrictionUsers(k, old, new string, d *schema.ResourceData) bool {
+	uuids := map[string]string{}
+	for _, v := range d.Get("user_accounts").([]interface{}) {
+		account := v.(map[string]interface{})
+		uuid := normalizeBranchRestrictionUser(account["uuid"].(string))
+		uuids[uuid] = uuid
+		if accountId := account["account_id"].(string); accountId != "" {
+			uuids[accountId] = uuid
+		}
+	}
+
+	canonical := func(users *schema.Set) *schema.Set {
+		set := schema.NewSet(schema.HashString, nil)
+		for _, v := range users.List() {
+			id := normalizeBranchRestrictionUser(v.(string))
+			if uuid, ok := uuids[id]; ok {
+				id = uuid
+			}
+			set.Add(id)
+		}
+		return set
+	}
+
+	o, n := d.GetChange("users")
+	return canonical(o.(*schema.Set)).Equal(canonical(n.(*schema.Set)))
+}
+
+func branchRestrictionGroupHash(v interface{}) int {
+	m := v.(map[string]interface{})
+	return schema.HashString(strings.ToLower(fmt.Sprintf("%s:%s", m["owner"], m["slug"])))
+}
+
+// flattenBranchRestrictionGroups identifies each group by the slug of its
+// workspace and its own slug, spelled like in current.
+func flattenBranchRestrictionGroups(groups []Group, current *schema.Set) []interface{} {
+	known := map[int]interface{}{}
+	for _, v := range current.List() {
+		known[branchRestrictionGroupHash(v)] = v
+	}
+
+	flat := make([]interface{}, 0, len(groups))
+	for _, group := range groups {
+		owner := ""
+		if group.Workspace != nil {
+			owner = group.Workspace.Slug
+		} else if i := strings.Index(group.FullSlug, ":"); i >= 0 {
+			owner = group.FullSlug[:i]
+		}
+
+		m := map[string]interface{}{
+			"owner": owner,
+			"slug":  group.Slug,
+		}
+		if v, ok := known[branchRestrictionGroupHash(m)]; ok {
+			m = v.(map[string]interface{})
+		}
+
+		flat = append(flat, m)
+	}
+
+	return flat
+}
+
+// suppressEquivalentBranchRestrictionGroups suppresses the diff of groups
+// whose slugs only differ in case.
+func suppressEquivalentBranchRestrictionGroups(k, old, new string, d *schema.ResourceData) bool {
+	o, n := d.GetChange("groups")
+	return o.(*schema.Set).HashEqual(n)
+}
+
+// branchRestrictionValueKinds are the kinds of branch restrictions that use
+// value, as a number of approvals, builds or commits.
+var branchRestrictionValueKinds = []string{
+	"require_approvals_to_merge",
+	"require_default_reviewer_approvals_to_merge",
+	"require_passing_builds_to_merge",
+	"require_commits_behind",
+}
+
+// branchRestrictionAccessKinds are the kinds of branch restrictions that apply
+// to everyone but users and groups.
+var branchRestrictionAccessKinds = []string{
+	"push",
+	"restrict_merges",
+}
+
+// customizeBranchRestrictionDiff rejects the arguments that the kind or
+// branch_match_kind of a branch restriction do not use, which Bitbucket only
+// rejects when applying.
+func customizeBranchRestrictionDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("kind") {
+		return nil
+	}
+	kind := d.Get("kind").(string)
+
+	if d.NewValueKnown("value") && d.Get("value").(int) != 0 && !slices.Contains(branchRestrictionValueKinds, kind) {
+		return fmt.Errorf("value cannot be set for kind %s, only for %s", kind, strings.Join(branchRestrictionValueKinds, ", "))
+	}
+
+	if !slices.Contains(branchRestrictionAccessKinds, kind) {
+		for _, key := range []string{"users", "groups"} {
+			if d.NewValueKnown(key) && d.Get(key).(*schema.Set).Len() > 0 {
+				return fmt.Errorf("%s cannot be set for kind %s, only for %s", key, kind, strings.Join(branchRestrictionAccessKinds, ", "))
+			}
+		}
+	}
+
+	if !d.NewValueKnown("branch_match_kind") || !d.NewValueKnown("pattern") || !d.NewValueKnown("branch_type") {
+		return nil
+	}
+
+	pattern := d.Get("pattern").(string)
+	branchType := d.Get("branch_type").(string)
+
+	switch d.Get("branch_match_kind").(string) {
+	case "glob":
+		if pattern == "" {
+			return errors.New(`pattern is required when branch_match_kind is "glob"`)
+		}
+		if branchType != "" {
+			return errors.New(`branch_type cannot be set when branch_match_kind is "glob", set branch_match_kind to "branching_model"`)
+		}
+	case "branching_model":
+		if branchType == "" {
+			return errors.New(`branch_type is required when branch_match_kind is "branching_model"`)
+		}
+		if pattern != "" {
+			return errors.New(`pattern cannot be set when branch_match_kind is "branching_model", set branch_match_kind to "glob"`)
+		}
+	}
+
+	return nil
+}
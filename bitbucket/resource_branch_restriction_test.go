@@ -1,14 +1,18 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -73,11 +77,275 @@ func TestAccBitbucketBranchRestriction_model(t *testing.T) {
 	})
 }
 
+func TestAccBitbucketBranchRestriction_usersAndGroups(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	testUser := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_branch_restriction.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketBranchRestrictionDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketBranchRestrictionUsersAndGroupsConfig(testUser, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketBranchRestrictionExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "kind", "push"),
+					resource.TestCheckResourceAttr(resourceName, "users.#", "1"),
+					resource.TestCheckTypeSetElemAttrPair(resourceName, "users.*", "data.bitbucket_current_user.test", "uuid"),
+					resource.TestCheckResourceAttrPair(resourceName, "user_accounts.0.uuid", "data.bitbucket_current_user.test", "uuid"),
+					resource.TestCheckResourceAttr(resourceName, "groups.#", "1"),
+					resource.TestCheckTypeSetElemNestedAttrs(resourceName, "groups.*", map[string]string{
+						"owner": testUser,
+						"slug":  rName,
+					}),
+				),
+			},
+			{
+				ResourceName:      resourceName,
+				ImportState:       true,
+				ImportStateIdFunc: testAccCheckBitbucketBranchRestrictionImportStateIdFunc(resourceName),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestResourceBranchRestrictionsRead_usersAndGroups(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = listResourceTestTransport{
+		"/2.0/repositories/ws/repo/branch-restrictions/1": `{
+			"id": 1,
+			"kind": "push",
+			"branch_match_kind": "glob",
+			"pattern": "main",
+			"users": [
+				{"uuid": "{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}", "account_id": "557058:0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f"},
+				{"uuid": "{7d2e4b1a-3f5c-4e8d-a9b0-1c2d3e4f5a6b}", "account_id": "5b10a2844c20165700ede21f"}
+			],
+			"groups": [
+				{"slug": "developers", "full_slug": "ws:developers", "workspace": {"slug": "ws"}}
+			]
+		}`,
+	}
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	clients, err := newClients(providerSettings{Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceBranchRestriction().TestResourceData()
+	d.SetId("1")
+	d.Set("owner", "ws")
+	d.Set("repository", "repo")
+	d.Set("users", []interface{}{"{0F3A9E3C-5C71-4A6E-9D6B-4A3C2B1E0D9F}", "5b10a2844c20165700ede21f"})
+	d.Set("groups", []interface{}{map[string]interface{}{"owner": "WS", "slug": "developers"}})
+
+	if diags := resourceBranchRestrictionsRead(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	// The users and groups are read back like they were configured.
+	expectedUsers := []interface{}{"5b10a2844c20165700ede21f", "{0F3A9E3C-5C71-4A6E-9D6B-4A3C2B1E0D9F}"}
+	if users := d.Get("users").(*schema.Set); !users.Equal(schema.NewSet(branchRestrictionUserHash, expectedUsers)) {
+		t.Errorf("expected users %v, got %v", expectedUsers, users.List())
+	}
+	if got := d.Get("user_accounts.1.account_id").(string); got != "5b10a2844c20165700ede21f" {
+		t.Errorf("unexpected account ID %q", got)
+	}
+	expectedGroups := []interface{}{map[string]interface{}{"owner": "WS", "slug": "developers"}}
+	if groups := d.Get("groups").(*schema.Set).List(); !reflect.DeepEqual(groups, expectedGroups) {
+		t.Errorf("expected groups %v, got %v", expectedGroups, groups)
+	}
+}
+
+func TestSuppressEquivalentBranchRestrictionUsers(t *testing.T) {
+	cases := map[string]struct {
+		old      []string
+		new      []string
+		suppress bool
+	}{
+		"same": {
+			old:      []string{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"},
+			new:      []string{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"},
+			suppress: true,
+		},
+		"uuid without braces": {
+			old:      []string{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"},
+			new:      []string{"0F3A9E3C-5C71-4A6E-9D6B-4A3C2B1E0D9F"},
+			suppress: true,
+		},
+		"account id of the same user": {
+			old:      []string{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"},
+			new:      []string{"557058:0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f"},
+			suppress: true,
+		},
+		"another user": {
+			old: []string{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"},
+			new: []string{"5b10a2844c20165700ede21f"},
+		},
+		"added user": {
+			old: []string{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"},
+			new: []string{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}", "5b10a2844c20165700ede21f"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := resourceBranchRestriction()
+
+			attributes := map[string]string{
+				"owner":                      "ws",
+				"repository":                 "repo",
+				"kind":                       "push",
+				"pattern":                    "main",
+				"user_accounts.#":            "1",
+				"user_accounts.0.uuid":       "{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}",
+				"user_accounts.0.account_id": "557058:0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f",
+				"users.#":                    fmt.Sprintf("%d", len(tc.old)),
+			}
+			for _, user := range tc.old {
+				attributes[fmt.Sprintf("users.%d", branchRestrictionUserHash(user))] = user
+			}
+
+			users := make([]interface{}, 0, len(tc.new))
+			for _, user := range tc.new {
+				users = append(users, user)
+			}
+
+			diff, err := r.Diff(context.Background(), &terraform.InstanceState{ID: "1", Attributes: attributes}, terraform.NewResourceConfigRaw(map[string]interface{}{
+				"owner":      "ws",
+				"repository": "repo",
+				"kind":       "push",
+				"pattern":    "main",
+				"users":      users,
+			}), nil)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			changed := false
+			if diff != nil {
+				for k, attr := range diff.Attributes {
+					if strings.HasPrefix(k, "users.") && attr.Old != attr.New {
+						changed = true
+					}
+				}
+			}
+			if changed == tc.suppress {
+				t.Errorf("expected the diff to be suppressed: %t, got diff %v", tc.suppress, diff)
+			}
+		})
+	}
+}
+
+func TestValidateBranchRestrictionUser(t *testing.T) {
+	cases := map[string]struct {
+		value string
+		err   string
+	}{
+		"uuid": {
+			value: "{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}",
+		},
+		"uuid without braces": {
+			value: "0F3A9E3C-5C71-4A6E-9D6B-4A3C2B1E0D9F",
+		},
+		"account id": {
+			value: "557058:0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f",
+		},
+		"legacy account id": {
+			value: "5b10a2844c20165700ede21f",
+		},
+		"username": {
+			value: "jdoe",
+			err:   "usernames are no longer supported, use the UUID or account ID",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, errs := validateBranchRestrictionUser(tc.value, "users")
+			switch {
+			case tc.err == "" && len(errs) > 0:
+				t.Fatalf("err: %s", errs[0])
+			case tc.err != "" && (len(errs) == 0 || !strings.Contains(errs[0].Error(), tc.err)):
+				t.Fatalf("expected error %q, got %v", tc.err, errs)
+			}
+		})
+	}
+}
+
+func TestCustomizeBranchRestrictionDiff(t *testing.T) {
+	cases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"approvals": {
+			config: map[string]interface{}{"kind": "require_approvals_to_merge", "pattern": "main", "value": 2},
+		},
+		"value of another kind": {
+			config: map[string]interface{}{"kind": "force", "pattern": "main", "value": 2},
+			err:    "value cannot be set for kind force",
+		},
+		"push users": {
+			config: map[string]interface{}{"kind": "push", "pattern": "main", "users": []interface{}{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"}},
+		},
+		"users of another kind": {
+			config: map[string]interface{}{"kind": "delete", "pattern": "main", "users": []interface{}{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"}},
+			err:    "users cannot be set for kind delete",
+		},
+		"groups of another kind": {
+			config: map[string]interface{}{"kind": "require_approvals_to_merge", "pattern": "main", "value": 1, "groups": []interface{}{map[string]interface{}{"owner": "ws", "slug": "developers"}}},
+			err:    "groups cannot be set for kind require_approvals_to_merge",
+		},
+		"glob without pattern": {
+			config: map[string]interface{}{"kind": "force"},
+			err:    "pattern is required",
+		},
+		"glob with branch type": {
+			config: map[string]interface{}{"kind": "force", "pattern": "main", "branch_type": "production"},
+			err:    "branch_type cannot be set",
+		},
+		"branching model": {
+			config: map[string]interface{}{"kind": "force", "branch_match_kind": "branching_model", "branch_type": "production"},
+		},
+		"branching model without branch type": {
+			config: map[string]interface{}{"kind": "force", "branch_match_kind": "branching_model"},
+			err:    "branch_type is required",
+		},
+		"branching model with pattern": {
+			config: map[string]interface{}{"kind": "force", "branch_match_kind": "branching_model", "branch_type": "production", "pattern": "main"},
+			err:    "pattern cannot be set",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := map[string]interface{}{"owner": "ws", "repository": "repo"}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+
+			_, err := resourceBranchRestriction().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(config), nil)
+			switch {
+			case tc.err == "" && err != nil:
+				t.Fatalf("err: %s", err)
+			case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
 func testAccBitbucketBranchRestrictionConfig(testUser, rName string) string {
 	return fmt.Sprintf(`
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 resource "bitbucket_branch_restriction" "test" {
   owner      = %[1]q
@@ -93,6 +361,8 @@ func testAccBitbucketBranchRestrictionModelConfig(testUser, rName string) string
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 resource "bitbucket_branch_restriction" "test" {
   owner             = %[1]q
@@ -104,6 +374,44 @@ resource "bitbucket_branch_restriction" "test" {
 `, testUser, rName)
 }
 
+func testAccBitbucketBranchRestrictionUsersAndGroupsConfig(testUser, rName string) string {
+	return fmt.Sprintf(`
+data "bitbucket_current_user" "test" {}
+
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  deletion_protection = false
+}
+
+resource "bitbucket_group" "test" {
+  workspace = %[1]q
+  name      = %[2]q
+}
+
+resource "bitbucket_repository_group_permission" "test" {
+  workspace  = %[1]q
+  repo_slug  = bitbucket_repository.test.name
+  group_slug = bitbucket_group.test.slug
+  permission = "write"
+}
+
+resource "bitbucket_branch_restriction" "test" {
+  owner      = %[1]q
+  repository = bitbucket_repository.test.name
+  kind       = "push"
+  pattern    = "main"
+  users      = [data.bitbucket_current_user.test.uuid]
+
+  groups {
+    owner = %[1]q
+    slug  = bitbucket_repository_group_permission.test.group_slug
+  }
+}
+`, testUser, rName)
+}
+
 func testAccCheckBitbucketBranchRestrictionDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(Clients).genClient
 	brApi := client.ApiClient.BranchRestrictionsApi
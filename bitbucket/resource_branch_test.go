@@ -0,0 +1,164 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketBranch_basic(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_branch.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketBranchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketBranchConfig(workspace, rName, "release/1.0", true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketBranchExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "workspace", workspace),
+					resource.TestCheckResourceAttr(resourceName, "name", "release/1.0"),
+					resource.TestCheckResourceAttr(resourceName, "source", "main"),
+					resource.TestCheckResourceAttrSet(resourceName, "target_hash"),
+					resource.TestCheckResourceAttr(resourceName, "id", fmt.Sprintf("%s/%s/release/1.0", workspace, rName)),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"source", "force_delete"},
+			},
+		},
+	})
+}
+
+func TestAccBitbucketBranch_alreadyExists(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketBranchDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config:      testAccBitbucketBranchConfig(workspace, rName, "main", true),
+				ExpectError: regexp.MustCompile(`Branch \(.+/main\) already exists`),
+			},
+		},
+	})
+}
+
+func TestResourceBranch_escapedName(t *testing.T) {
+	clients, err := newClients(providerSettings{
+		Username: "user",
+		Password: "password",
+		HTTPClient: &http.Client{Transport: listResourceTestTransport{
+			"/2.0/repositories/ws/repo/refs/branches/release%2F1.0%23x": `{"name": "release/1.0#x", "target": {"hash": "4f3b3c2a1d0e"}}`,
+			"/2.0/repositories/ws/repo/commit/release%2F1.0%23x":        `{"hash": "4f3b3c2a1d0e"}`,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceBranch().Data(&terraform.InstanceState{
+		ID:         "ws/repo/release/1.0#x",
+		Attributes: map[string]string{"force_delete": "true"},
+	})
+
+	if diags := resourceBranchRead(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error reading the branch: %v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatalf("expected the branch to be found")
+	}
+	if name := d.Get("name").(string); name != "release/1.0#x" {
+		t.Errorf("expected name %q, got %q", "release/1.0#x", name)
+	}
+	if hash := d.Get("target_hash").(string); hash != "4f3b3c2a1d0e" {
+		t.Errorf("expected target_hash %q, got %q", "4f3b3c2a1d0e", hash)
+	}
+
+	hash, err := resolveCommit(clients, "ws", "repo", "release/1.0#x")
+	if err != nil {
+		t.Fatalf("unexpected error resolving the branch: %s", err)
+	}
+	if hash != "4f3b3c2a1d0e" {
+		t.Errorf("expected the branch to resolve to %q, got %q", "4f3b3c2a1d0e", hash)
+	}
+
+	if diags := resourceBranchDelete(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error deleting the branch: %v", diags)
+	}
+}
+
+func testAccCheckBitbucketBranchDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).httpClient
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_branch" || rs.Primary.Attributes["force_delete"] != "true" {
+			continue
+		}
+
+		workspace, repoSlug, name, err := branchId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		branch, err := getBranch(&client, workspace, repoSlug, name)
+		if err != nil {
+			return fmt.Errorf("unexpected error checking Branch (%s): %s", rs.Primary.ID, err)
+		}
+		if branch != nil {
+			return fmt.Errorf("Branch (%s) still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckBitbucketBranchExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Branch ID is set")
+		}
+		return nil
+	}
+}
+
+func testAccBitbucketBranchConfig(workspace, rName, name string, forceDelete bool) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_branch" "test" {
+  workspace    = bitbucket_repository.test.owner
+  repo_slug    = bitbucket_repository.test.slug
+  name         = %[3]q
+  source       = "main"
+  force_delete = %[4]t
+}
+`, workspace, rName, name, forceDelete)
+}
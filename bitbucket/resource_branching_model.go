@@ -36,15 +36,16 @@ type BranchType struct {
 	Prefix  string `json:"prefix,omitempty"`
 }
 
+var branchingModelIdentity = newResourceIdentity("/", "workspace", "repo_slug")
+
 func resourceBranchingModel() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceBranchingModelsPut,
 		ReadWithoutTimeout:   resourceBranchingModelsRead,
 		UpdateWithoutTimeout: resourceBranchingModelsPut,
 		DeleteWithoutTimeout: resourceBranchingModelsDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             branchingModelIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             branchingModelIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"owner": {
@@ -214,6 +215,10 @@ func resourceBranchingModelsRead(ctx context.Context, d *schema.ResourceData, m
 	d.Set("branch_type", flattenBranchTypes(branchingModel.BranchTypes))
 	d.Set("production", flattenBranchModel(branchingModel.Production, "production"))
 
+	if err := branchingModelIdentity.set(d, owner, repo); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -110,6 +110,8 @@ func testAccBitbucketBranchingModelConfig(testUser, rName string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 resource "bitbucket_branching_model" "test" {
   owner      = %[1]q
@@ -127,6 +129,8 @@ func testAccBitbucketBranchingModelProdConfig(testUser, rName string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 resource "bitbucket_branching_model" "test" {
   owner      = %[1]q
@@ -149,6 +153,8 @@ func testAccBitbucketBranchingModelBranchTypesConfig1(testUser, rName string) st
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 resource "bitbucket_branching_model" "test" {
   owner      = %[1]q
@@ -3,24 +3,54 @@ package bitbucket
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
 	"mime/multipart"
 	"net/http"
+	"net/url"
+	"os"
 	"strings"
 
-	"github.com/strollby/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+var commitFileIdentity = newResourceIdentity("/", "workspace", "repo_slug", "branch", "filename")
+
 func resourceCommitFile() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceCommitFilePut,
 		ReadWithoutTimeout:   resourceCommitFileRead,
+		UpdateWithoutTimeout: resourceCommitFileUpdate,
 		DeleteWithoutTimeout: resourceCommitFileDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		CustomizeDiff:        customizeCommitFileDiff,
+		Importer: commitFileIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			// Branches and file names may contain slashes, so prefer the identity when importing by identity.
+			idParts, err := commitFileIdentity.values(d)
+			if err != nil {
+				idParts = strings.SplitN(d.Id(), "/", 4)
+			}
+			if len(idParts) != 4 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" || idParts[3] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/BRANCH/FILENAME", d.Id())
+			}
+			d.Set("workspace", idParts[0])
+			d.Set("repo_slug", idParts[1])
+			d.Set("branch", idParts[2])
+			d.Set("filename", idParts[3])
+			d.Set("delete_on_destroy", true)
+			d.Set("normalize_line_endings", false)
+			d.Set("strict_parent", false)
+			d.Set("rebase_on_conflict", false)
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: commitFileIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -34,9 +64,41 @@ func resourceCommitFile() *schema.Resource {
 				ForceNew: true,
 			},
 			"content": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"content", "content_base64", "source"},
+			},
+			"content_base64": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"content", "content_base64", "source"},
+				ValidateFunc: validation.StringIsBase64,
+				Description:  "The base64 encoded content of the file, for binary files",
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"content", "content_base64", "source"},
+				Description:  "The path of a local file to commit the content of",
+			},
+			"executable": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"symlink"},
+				Description:   "Whether the file is executable",
+			},
+			"symlink": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"executable"},
+				Description:   "Whether the file is a symbolic link to the path in its content",
+			},
+			"content_sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA-256 of the content of the file on the branch",
 			},
 			"filename": {
 				Type:     schema.TypeString,
@@ -51,20 +113,55 @@ func resourceCommitFile() *schema.Resource {
 			"commit_message": {
 				Type:        schema.TypeString,
 				Required:    true,
-				Description: "The SHA of the commit that modified the file",
-				ForceNew:    true,
+				Description: "The message of the commits that modify the file",
 			},
-			"commit_author": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "The SHA of the commit that modified the file",
-				ForceNew:    true,
+			"commit_author": commitAuthorSchema(),
+			"delete_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether destroying the resource commits the removal of the file",
+			},
+			"normalize_line_endings": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether CRLF and LF line endings are equal when comparing the content of the file on the branch",
+			},
+			"expected_parent": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"strict_parent"},
+				Description:   "The commit the head of the branch must be at for the commit of the file",
+			},
+			"strict_parent": {
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       false,
+				ConflictsWith: []string{"expected_parent"},
+				Description:   "Whether the head of the branch must still be at head_sha for the commit of the file",
+			},
+			"rebase_on_conflict": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the file is committed on top of the new head of a branch that moved, unless the file was changed by the new commits",
 			},
 			"commit_sha": {
 				Type:        schema.TypeString,
 				Computed:    true,
 				Description: "The SHA of the commit that modified the file",
 			},
+			"head_sha": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The head of the branch when it was last read",
+			},
+			"last_commit_sha": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SHA of the last commit that modified the file on the branch",
+			},
 		},
 	}
 }
@@ -74,45 +171,327 @@ func resourceCommitFilePut(ctx context.Context, d *schema.ResourceData, m interf
 
 	repoSlug := d.Get("repo_slug").(string)
 	workspace := d.Get("workspace").(string)
-	content := d.Get("content").(string)
 	filename := d.Get("filename").(string)
 	branch := d.Get("branch").(string)
-	commitMessage := d.Get("commit_message").(string)
-	commitAuthor := d.Get("commit_author").(string)
 
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	part, _ := writer.CreateFormFile(filename, filename)
-	_, err := part.Write([]byte(content))
+	commit, err := newCommitFileCommit(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	defer writer.Close()
 
-	messageFormField, err := writer.CreateFormField("message")
+	// With strict_parent, the file is committed on top of the head read
+	// when planning, or on creation, the current head.
+	parent := d.Get("expected_parent").(string)
+	if d.Get("strict_parent").(bool) {
+		parent = d.Get("head_sha").(string)
+		if d.IsNewResource() || parent == "" {
+			parent, err = getBranchHash(&client, workspace, repoSlug, branch)
+			if err != nil {
+				return diag.Errorf("error reading branch of Commit File (%s/%s/%s/%s): %s", workspace, repoSlug, branch, filename, err)
+			}
+		}
+	}
+
+	commitSha, err := commitFileOnParent(&client, workspace, repoSlug, commit, filename, parent, d.Get("rebase_on_conflict").(bool))
+	var moved *branchMoved
+	if errors.As(err, &moved) {
+		detail := "The branch was pushed to since it was last read. Refresh and apply again to commit on top of the new head, or set rebase_on_conflict to do so when the file was left unchanged."
+		if d.Get("expected_parent").(string) != "" {
+			detail = "The head of the branch is no longer expected_parent. Update expected_parent to commit on top of the new head, or set rebase_on_conflict to do so when the file was left unchanged."
+		}
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("error committing %s: %s", filename, moved),
+			Detail:   detail,
+		}}
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	_, err = messageFormField.Write([]byte(commitMessage))
+
+	d.SetId(string(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, branch, filename)))
+	d.Set("commit_sha", commitSha)
+
+	return resourceCommitFileRead(ctx, d, m)
+}
+
+func resourceCommitFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	repoSlug := d.Get("repo_slug").(string)
+	workspace := d.Get("workspace").(string)
+	filename := d.Get("filename").(string)
+	branch := d.Get("branch").(string)
+
+	// The file is read at the head of the branch rather than at commit_sha,
+	// so changes made to it since are noticed.
+	head, err := getBranchHash(&client, workspace, repoSlug, branch)
 	if err != nil {
-		return diag.FromErr(err)
+		return diag.Errorf("error reading branch of Commit File (%s): %s", d.Id(), err)
 	}
-	authorFormField, err := writer.CreateFormField("author")
+
+	var meta *srcFileMeta
+	if head != "" {
+		meta, err = getSrcFileMeta(&client, workspace, repoSlug, head, filename)
+		if err != nil {
+			return diag.Errorf("error reading Commit File (%s): %s", d.Id(), err)
+		}
+	}
+
+	if meta == nil {
+		log.Printf("[WARN] Commit File (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	content, err := getSrcFile(&client, workspace, repoSlug, head, filename)
 	if err != nil {
+		return diag.Errorf("error reading Commit File (%s): %s", d.Id(), err)
+	}
+
+	// With normalized line endings, both hashes are of the normalized
+	// content, so they only differ on actual changes.
+	normalize := d.Get("normalize_line_endings").(bool)
+	hash := commitFileSHA256(content, normalize)
+
+	// Only content is read back, the content of base64 or of a local file
+	// is compared through its hash.
+	if d.Get("content_base64").(string) == "" && d.Get("source").(string) == "" {
+		if hash != commitFileSHA256([]byte(d.Get("content").(string)), normalize) {
+			log.Printf("[DEBUG] Content of Commit File (%s) changed on %s", d.Id(), branch)
+			d.Set("content", string(content))
+		}
+	}
+
+	d.Set("content_sha256", hash)
+	d.Set("executable", meta.hasAttribute("executable"))
+	d.Set("symlink", meta.hasAttribute("link"))
+	d.Set("last_commit_sha", meta.Commit.Hash)
+	d.Set("head_sha", head)
+
+	if err := commitFileIdentity.set(d, workspace, repoSlug, branch, filename); err != nil {
 		return diag.FromErr(err)
 	}
-	_, err = authorFormField.Write([]byte(commitAuthor))
+
+	return nil
+}
+
+// newCommitFileCommit returns the commit of the file of d, before its parent
+// is chosen.
+func newCommitFileCommit(d *schema.ResourceData) (srcCommit, error) {
+	filename := d.Get("filename").(string)
+
+	content, err := commitFileContent(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return srcCommit{}, err
+	}
+
+	commit := srcCommit{
+		message: d.Get("commit_message").(string),
+		author:  d.Get("commit_author").(string),
+		branch:  d.Get("branch").(string),
+		files: map[string][]byte{
+			filename: content,
+		},
+	}
+	if d.Get("executable").(bool) {
+		commit.executables = []string{filename}
+	}
+	if d.Get("symlink").(bool) {
+		commit.symlinks = []string{filename}
 	}
 
-	branchFormField, err := writer.CreateFormField("branch")
+	return commit, nil
+}
+
+// commitFileContent returns the content to commit, from either content,
+// content_base64 or source.
+func commitFileContent(d interface{ Get(string) interface{} }) ([]byte, error) {
+	if v, _ := d.Get("content_base64").(string); v != "" {
+		content, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding content_base64: %w", err)
+		}
+		return content, nil
+	}
+
+	if v, _ := d.Get("source").(string); v != "" {
+		content, err := os.ReadFile(v)
+		if err != nil {
+			return nil, fmt.Errorf("error reading source: %w", err)
+		}
+		return content, nil
+	}
+
+	v, _ := d.Get("content").(string)
+	return []byte(v), nil
+}
+
+// commitFileSHA256 returns the SHA-256 of content, optionally with its CRLF
+// line endings replaced by LF.
+func commitFileSHA256(content []byte, normalizeLineEndings bool) string {
+	if normalizeLineEndings {
+		content = bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// customizeCommitFileDiff plans a commit when the content to commit differs
+// from the content on the branch.
+func customizeCommitFileDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range []string{"content", "content_base64", "source", "normalize_line_endings"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("content_sha256")
+		}
+	}
+
+	content, err := commitFileContent(d)
 	if err != nil {
-		return diag.FromErr(err)
+		return err
+	}
+
+	if hash := commitFileSHA256(content, d.Get("normalize_line_endings").(bool)); hash != d.Get("content_sha256").(string) {
+		return d.SetNew("content_sha256", hash)
+	}
+
+	return nil
+}
+
+func resourceCommitFileUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// A new commit is only needed for new content or a new mode, the other
+	// arguments only apply to the next commit.
+	if d.HasChanges("content_sha256", "executable", "symlink") {
+		return resourceCommitFilePut(ctx, d, m)
+	}
+
+	return resourceCommitFileRead(ctx, d, m)
+}
+
+func resourceCommitFileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.Get("delete_on_destroy").(bool) {
+		log.Printf("[DEBUG] Commit File (%s) is kept in the repository, as delete_on_destroy is not set", d.Id())
+		return nil
 	}
-	_, err = branchFormField.Write([]byte(branch))
+
+	client := m.(Clients).httpClient
+
+	repoSlug := d.Get("repo_slug").(string)
+	workspace := d.Get("workspace").(string)
+	filename := d.Get("filename").(string)
+	branch := d.Get("branch").(string)
+
+	head, err := getBranchHash(&client, workspace, repoSlug, branch)
 	if err != nil {
-		return diag.FromErr(err)
+		return diag.Errorf("error reading branch of Commit File (%s): %s", d.Id(), err)
+	}
+
+	var meta *srcFileMeta
+	if head != "" {
+		meta, err = getSrcFileMeta(&client, workspace, repoSlug, head, filename)
+		if err != nil {
+			return diag.Errorf("error reading Commit File (%s): %s", d.Id(), err)
+		}
+	}
+
+	if meta == nil {
+		log.Printf("[DEBUG] Commit File (%s) no longer exists on its branch, nothing to delete", d.Id())
+		return nil
+	}
+
+	commit := srcCommit{
+		message: fmt.Sprintf("Delete %s", filename),
+		author:  d.Get("commit_author").(string),
+		branch:  branch,
+		deleted: []string{filename},
+	}
+
+	if _, err := commit.create(&client, workspace, repoSlug); err != nil {
+		return diag.Errorf("error deleting Commit File (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// commitAuthorSchema returns the schema of the author of the commits made by
+// a resource. Bitbucket defaults it to the user of the provider.
+func commitAuthorSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeString,
+		Optional:    true,
+		Description: "The author of the commits, e.g. `Name <email>`, the user of the provider by default",
+	}
+}
+
+// srcCommit is a commit created through the src endpoint, adding, replacing
+// or deleting files on top of the head of branch.
+type srcCommit struct {
+	message string
+	author  string
+	branch  string
+	files   map[string][]byte
+	// deleted lists the paths of the files to delete.
+	deleted []string
+	// executables and symlinks list the paths of the files committed as
+	// executable files and symbolic links.
+	executables []string
+	symlinks    []string
+	// parents are the expected parents of the commit. Bitbucket rejects the
+	// commit when the head of branch is not among them.
+	parents []string
+}
+
+// create posts the commit and returns its hash.
+func (sc srcCommit) create(client *Client, workspace, repoSlug string) (string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	for filename, content := range sc.files {
+		part, err := writer.CreateFormFile(filename, filename)
+		if err != nil {
+			return "", err
+		}
+		if _, err := part.Write(content); err != nil {
+			return "", err
+		}
+	}
+
+	fields := map[string]string{
+		"message": sc.message,
+		"author":  sc.author,
+		"branch":  sc.branch,
+		"parents": strings.Join(sc.parents, ","),
+	}
+	for name, value := range fields {
+		if value == "" {
+			continue
+		}
+		if err := writer.WriteField(name, value); err != nil {
+			return "", err
+		}
+	}
+
+	for _, path := range sc.executables {
+		if err := writer.WriteField("executables", path); err != nil {
+			return "", err
+		}
+	}
+	for _, path := range sc.symlinks {
+		if err := writer.WriteField("symlinks", path); err != nil {
+			return "", err
+		}
+	}
+
+	// Paths sent in the files field without content are deleted.
+	for _, path := range sc.deleted {
+		if err := writer.WriteField("files", path); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return "", err
 	}
 
 	response, err := client.PostWithContentType(fmt.Sprintf("2.0/repositories/%s/%s/src",
@@ -121,40 +500,155 @@ func resourceCommitFilePut(ctx context.Context, d *schema.ResourceData, m interf
 	), writer.FormDataContentType(), body)
 
 	if err := handleClientError(err); err != nil {
-		return diag.FromErr(err)
+		return "", err
 	}
 
 	if response.StatusCode != http.StatusCreated {
-		return diag.FromErr(fmt.Errorf(""))
+		return "", fmt.Errorf("unexpected status %d committing to %s/%s", response.StatusCode, workspace, repoSlug)
 	}
 
-	d.SetId(string(fmt.Sprintf("%s/%s/%s/%s", workspace, repoSlug, branch, filename)))
-
-	location, _ := response.Location()
+	location, err := response.Location()
+	if err != nil {
+		return "", err
+	}
 	splitPath := strings.Split(location.Path, "/")
-	d.Set("commit_sha", splitPath[len(splitPath)-1])
 
-	return resourceCommitFileRead(ctx, d, m)
+	return splitPath[len(splitPath)-1], nil
 }
 
-func resourceCommitFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(Clients).genClient
-	sourceApi := c.ApiClient.SourceApi
+// commitFileRebaseAttempts is how many times a commit of a file is rebased
+// on a branch that keeps moving.
+const commitFileRebaseAttempts = 3
 
-	repoSlug := d.Get("repo_slug").(string)
-	workspace := d.Get("workspace").(string)
-	filename := d.Get("filename").(string)
-	commit := d.Get("commit_sha").(string)
+// commitFileOnParent creates commit with parent as its expected parent, unless
+// parent is empty. When the branch moved on from parent, and rebase is set, the
+// commit is created again on top of the new head, as long as the commits in
+// between left filename unchanged.
+func commitFileOnParent(client *Client, workspace, repoSlug string, commit srcCommit, filename, parent string, rebase bool) (string, error) {
+	for attempt := 0; ; attempt++ {
+		if parent != "" {
+			commit.parents = []string{parent}
+		}
 
-	_, _, err := sourceApi.RepositoriesWorkspaceRepoSlugSrcCommitPathGet(c.AuthContext, commit, filename, repoSlug, workspace, &bitbucket.SourceApiRepositoriesWorkspaceRepoSlugSrcCommitPathGetOpts{})
+		commitSha, err := commit.create(client, workspace, repoSlug)
+		if err == nil || parent == "" {
+			return commitSha, err
+		}
 
-	if err := handleClientError(err); err != nil {
-		return diag.FromErr(err)
+		head, headErr := getBranchHash(client, workspace, repoSlug, commit.branch)
+		if headErr != nil || head == "" || head == parent {
+			return "", err
+		}
+
+		if !rebase || attempt == commitFileRebaseAttempts {
+			return "", &branchMoved{branch: commit.branch, expected: parent, head: head}
+		}
+
+		expected, err := getSrcFile(client, workspace, repoSlug, parent, filename)
+		if err != nil {
+			return "", err
+		}
+		current, err := getSrcFile(client, workspace, repoSlug, head, filename)
+		if err != nil {
+			return "", err
+		}
+		if !bytes.Equal(expected, current) || (expected == nil) != (current == nil) {
+			return "", fmt.Errorf("%s was changed on branch %s between %s and %s, refresh and apply again to overwrite the changes", filename, commit.branch, parent, head)
+		}
+
+		log.Printf("[DEBUG] Branch %s moved from %s to %s without changes to %s, committing on top of %s", commit.branch, parent, head, filename, head)
+		parent = head
+	}
+}
+
+// branchMovedError returns an error when branch moved on from expected, which
+// explains why a commit with expected as parent was rejected.
+func branchMovedError(client *Client, workspace, repoSlug, branch, expected string) error {
+	head, err := getBranchHash(client, workspace, repoSlug, branch)
+	if err != nil || head == expected {
+		return nil
 	}
 
-	return nil
+	return fmt.Errorf("%w since it was last read, refresh and apply again to commit on top of the new head", &branchMoved{branch: branch, expected: expected, head: head})
 }
 
-func resourceCommitFileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	return nil
+// branchMoved is the error of a commit rejected because the head of branch
+// is no longer at its expected parent.
+type branchMoved struct {
+	branch   string
+	expected string
+	head     string
+}
+
+func (e *branchMoved) Error() string {
+	return fmt.Sprintf("branch %s moved from %s to %s", e.branch, e.expected, e.head)
+}
+
+// srcFileMeta is the metadata of a file of the src endpoint.
+type srcFileMeta struct {
+	Path     string `json:"path"`
+	Type     string `json:"type"`
+	Size     int    `json:"size"`
+	Mimetype string `json:"mimetype"`
+	// Attributes holds executable for executable files and link for
+	// symbolic links.
+	Attributes []string `json:"attributes"`
+	// Commit is the last commit that modified the file.
+	Commit struct {
+		Hash string `json:"hash"`
+	} `json:"commit"`
+}
+
+func (meta *srcFileMeta) hasAttribute(attribute string) bool {
+	for _, a := range meta.Attributes {
+		if a == attribute {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getSrcFileMeta returns the metadata of the file at path in commit, or nil
+// when it does not exist.
+func getSrcFileMeta(client *Client, workspace, repoSlug, commit, path string) (*srcFileMeta, error) {
+	res, err := client.Get(srcEndpoint(workspace, repoSlug, commit, path) + "?format=meta")
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var meta srcFileMeta
+	if err := json.NewDecoder(res.Body).Decode(&meta); err != nil {
+		return nil, err
+	}
+
+	return &meta, nil
+}
+
+// getSrcFile returns the raw content of the file at path in commit, or nil
+// when it does not exist.
+func getSrcFile(client *Client, workspace, repoSlug, commit, path string) ([]byte, error) {
+	res, err := client.Get(srcEndpoint(workspace, repoSlug, commit, path))
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return io.ReadAll(res.Body)
+}
+
+func srcEndpoint(workspace, repoSlug, commit, path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	return fmt.Sprintf("2.0/repositories/%s/%s/src/%s/%s", workspace, repoSlug, commit, strings.Join(segments, "/"))
 }
@@ -1,12 +1,21 @@
 package bitbucket
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/strollby/bitbucket-go-client"
 )
 
 func testAccBitbucketCommitFileConfig(owner, rName string) string {
@@ -14,6 +23,8 @@ func testAccBitbucketCommitFileConfig(owner, rName string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_commit_file" "test" {
@@ -50,3 +61,614 @@ func TestAccBitbucketCommitFile_basic(t *testing.T) {
 		},
 	})
 }
+
+func TestAccBitbucketCommitFile_update(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	owner := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_commit_file.test"
+	var commitSha string
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketCommitFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitFileContentConfig(owner, rName, "abc"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content", "abc"),
+					resource.TestCheckResourceAttr(resourceName, "delete_on_destroy", "true"),
+					resource.TestCheckResourceAttrWith(resourceName, "commit_sha", func(value string) error {
+						commitSha = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccBitbucketCommitFileContentConfig(owner, rName, "def"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content", "def"),
+					resource.TestCheckResourceAttrWith(resourceName, "commit_sha", func(value string) error {
+						if value == commitSha {
+							return fmt.Errorf("expected a new commit, got %s again", value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				// Destroying the file only commits its removal.
+				Config: testAccBitbucketCommitFileRepositoryConfig(owner, rName),
+				Check:  testAccCheckBitbucketCommitFileDeleted(owner, rName, "main", "docs/CONTENT.md"),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketCommitFile_drift(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	owner := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_commit_file.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketCommitFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitFileContentConfig(owner, rName, "abc"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "last_commit_sha", resourceName, "commit_sha"),
+				),
+			},
+			{
+				// Someone else changes the file on the branch.
+				PreConfig: func() {
+					client := testAccProvider.Meta().(Clients).httpClient
+					commit := srcCommit{
+						message: "Overwrite content",
+						branch:  "main",
+						files: map[string][]byte{
+							"docs/CONTENT.md": []byte("xyz"),
+						},
+					}
+					if _, err := commit.create(&client, owner, rName); err != nil {
+						t.Fatalf("err: %s", err)
+					}
+				},
+				Config:             testAccBitbucketCommitFileContentConfig(owner, rName, "abc"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccBitbucketCommitFileContentConfig(owner, rName, "abc"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content", "abc"),
+					resource.TestCheckResourceAttrPair(resourceName, "last_commit_sha", resourceName, "commit_sha"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketCommitFile_binary(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	owner := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_commit_file.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketCommitFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitFileBinaryConfig(owner, rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content_base64", "iVBORw0KGgoAAAAA"),
+					resource.TestCheckResourceAttr(resourceName, "content_sha256", commitFileSHA256([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x00"), false)),
+					resource.TestCheckResourceAttr(resourceName, "executable", "false"),
+				),
+			},
+			{
+				// Only the mode changes, which needs a new commit too.
+				Config: testAccBitbucketCommitFileBinaryConfig(owner, rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "executable", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketCommitFile_expectedParent(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	owner := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_commit_file.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketCommitFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitFileExpectedParentConfig(owner, rName, "abc"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "expected_parent", "bitbucket_branch.test", "target_hash"),
+					resource.TestCheckResourceAttrPair(resourceName, "head_sha", resourceName, "commit_sha"),
+				),
+			},
+			{
+				// The branch is read again when planning, so its head is the
+				// first commit of the file.
+				Config: testAccBitbucketCommitFileExpectedParentConfig(owner, rName, "def"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content", "def"),
+					resource.TestCheckResourceAttrPair(resourceName, "head_sha", resourceName, "commit_sha"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckBitbucketCommitFileDestroy(s *terraform.State) error {
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_commit_file" || rs.Primary.Attributes["delete_on_destroy"] != "true" {
+			continue
+		}
+
+		attributes := rs.Primary.Attributes
+		if err := testAccCheckBitbucketCommitFileDeleted(attributes["workspace"], attributes["repo_slug"], attributes["branch"], attributes["filename"])(s); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckBitbucketCommitFileDeleted(workspace, repoSlug, branch, filename string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		c := testAccProvider.Meta().(Clients).genClient
+		sourceApi := c.ApiClient.SourceApi
+
+		_, res, err := sourceApi.RepositoriesWorkspaceRepoSlugSrcCommitPathGet(c.AuthContext, branch, filename, repoSlug, workspace, &bitbucket.SourceApiRepositoriesWorkspaceRepoSlugSrcCommitPathGetOpts{})
+		if res == nil || res.StatusCode != http.StatusNotFound {
+			return fmt.Errorf("file %s still exists on %s/%s@%s: %v", filename, workspace, repoSlug, branch, err)
+		}
+
+		return nil
+	}
+}
+
+func testAccBitbucketCommitFileRepositoryConfig(owner, rName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+`, owner, rName)
+}
+
+func testAccBitbucketCommitFileContentConfig(owner, rName, content string) string {
+	return testAccBitbucketCommitFileRepositoryConfig(owner, rName) + fmt.Sprintf(`
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = "main"
+  filename       = "docs/CONTENT.md"
+  content        = %[1]q
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "Update content"
+}
+`, content)
+}
+
+func testAccBitbucketCommitFileBinaryConfig(owner, rName string, executable bool) string {
+	return testAccBitbucketCommitFileRepositoryConfig(owner, rName) + fmt.Sprintf(`
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = "main"
+  filename       = "bin/image.png"
+  content_base64 = "iVBORw0KGgoAAAAA"
+  executable     = %[1]t
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "Update image"
+}
+`, executable)
+}
+
+func testAccBitbucketCommitFileExpectedParentConfig(owner, rName, content string) string {
+	return testAccBitbucketCommitFileRepositoryConfig(owner, rName) + fmt.Sprintf(`
+resource "bitbucket_branch" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  name      = "feature"
+  source    = "main"
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace       = bitbucket_repository.test.owner
+  repo_slug       = bitbucket_repository.test.slug
+  branch          = bitbucket_branch.test.name
+  filename        = "docs/CONTENT.md"
+  content         = %[1]q
+  expected_parent = bitbucket_branch.test.target_hash
+  commit_author   = "Unit test <unit@test.local>"
+  commit_message  = "Update content"
+}
+`, content)
+}
+
+// testSrcTransport accepts commits to the src endpoint, recording the
+// multipart forms posted.
+type testSrcTransport struct {
+	requests []*http.Request
+}
+
+func (t *testSrcTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		return nil, err
+	}
+	t.requests = append(t.requests, req)
+
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": []string{"https://api.bitbucket.org/2.0/repositories/ws/repo/commit/abc123"}},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestSrcCommit_create(t *testing.T) {
+	transport := &testSrcTransport{}
+	client := &Client{HTTPClient: &http.Client{Transport: transport}}
+
+	commit := srcCommit{
+		message: "Replace the changelog",
+		author:  "Unit test <unit@test.local>",
+		branch:  "main",
+		files: map[string][]byte{
+			"docs/README.md": []byte("# Docs\n"),
+		},
+		deleted:     []string{"CHANGELOG.md", "docs/CHANGES.md"},
+		executables: []string{"docs/README.md"},
+	}
+
+	hash, err := commit.create(client, "ws", "repo")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if hash != "abc123" {
+		t.Errorf("expected hash abc123, got %q", hash)
+	}
+
+	if len(transport.requests) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(transport.requests))
+	}
+	req := transport.requests[0]
+
+	if req.URL.Path != "/2.0/repositories/ws/repo/src" {
+		t.Errorf("unexpected path %s", req.URL.Path)
+	}
+
+	for field, expected := range map[string][]string{
+		"message":     {"Replace the changelog"},
+		"author":      {"Unit test <unit@test.local>"},
+		"branch":      {"main"},
+		"files":       {"CHANGELOG.md", "docs/CHANGES.md"},
+		"executables": {"docs/README.md"},
+		"symlinks":    nil,
+	} {
+		if got := req.MultipartForm.Value[field]; !reflect.DeepEqual(got, expected) {
+			t.Errorf("expected field %s to be %q, got %q", field, expected, got)
+		}
+	}
+
+	file, ok := req.MultipartForm.File["docs/README.md"]
+	if !ok || len(file) != 1 {
+		t.Fatalf("expected the file docs/README.md, got %v", req.MultipartForm.File)
+	}
+	f, err := file[0].Open()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer f.Close()
+	if content, _ := io.ReadAll(f); string(content) != "# Docs\n" {
+		t.Errorf("unexpected content %q", content)
+	}
+}
+
+func TestCommitFileContent(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "image.png")
+	if err := os.WriteFile(source, []byte("\x89PNG"), 0o644); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for name, tc := range map[string]struct {
+		values   map[string]interface{}
+		expected string
+		err      bool
+	}{
+		"content": {
+			values:   map[string]interface{}{"content": "abc"},
+			expected: "abc",
+		},
+		"content_base64": {
+			values:   map[string]interface{}{"content_base64": "iVBORw=="},
+			expected: "\x89PNG",
+		},
+		"invalid content_base64": {
+			values: map[string]interface{}{"content_base64": "%"},
+			err:    true,
+		},
+		"source": {
+			values:   map[string]interface{}{"source": source},
+			expected: "\x89PNG",
+		},
+		"missing source": {
+			values: map[string]interface{}{"source": filepath.Join(t.TempDir(), "missing")},
+			err:    true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			content, err := commitFileContent(testGetter(tc.values))
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got content %q", content)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if string(content) != tc.expected {
+				t.Errorf("expected content %q, got %q", tc.expected, content)
+			}
+		})
+	}
+}
+
+// testGetter serves attribute values like schema.ResourceData.
+type testGetter map[string]interface{}
+
+func (g testGetter) Get(key string) interface{} {
+	return g[key]
+}
+
+// testMovedBranchTransport serves a branch whose head is at head, and rejects
+// commits with another parent than head.
+type testMovedBranchTransport struct {
+	head    string
+	files   listResourceTestTransport
+	commits []string
+}
+
+func (t *testMovedBranchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		if req.URL.Path == "/2.0/repositories/ws/repo/refs/branches/main" {
+			return listResourceTestTransport{req.URL.RequestURI(): fmt.Sprintf(`{"name": "main", "target": {"hash": %q}}`, t.head)}.RoundTrip(req)
+		}
+		return t.files.RoundTrip(req)
+	}
+
+	if err := req.ParseMultipartForm(1 << 20); err != nil {
+		return nil, err
+	}
+	parents := req.MultipartForm.Value["parents"]
+	t.commits = append(t.commits, strings.Join(parents, ","))
+	if len(parents) > 0 && parents[0] != t.head {
+		return &http.Response{
+			StatusCode: http.StatusConflict,
+			Body:       io.NopCloser(strings.NewReader(`{"error": {"message": "parents mismatch"}}`)),
+			Request:    req,
+		}, nil
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Location": []string{"https://api.bitbucket.org/2.0/repositories/ws/repo/commit/abc123"}},
+		Body:       io.NopCloser(strings.NewReader("")),
+		Request:    req,
+	}, nil
+}
+
+func TestCommitFileOnParent(t *testing.T) {
+	for name, tc := range map[string]struct {
+		parent  string
+		rebase  bool
+		files   listResourceTestTransport
+		commits []string
+		moved   bool
+		err     bool
+	}{
+		"no parent": {
+			commits: []string{""},
+		},
+		"expected parent": {
+			parent:  "new",
+			commits: []string{"new"},
+		},
+		"branch moved": {
+			parent:  "old",
+			commits: []string{"old"},
+			moved:   true,
+		},
+		"branch moved and rebased": {
+			parent: "old",
+			rebase: true,
+			files: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/src/old/README.md": "abc",
+				"/2.0/repositories/ws/repo/src/new/README.md": "abc",
+			},
+			commits: []string{"old", "new"},
+		},
+		"branch moved and file changed": {
+			parent: "old",
+			rebase: true,
+			files: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/src/old/README.md": "abc",
+				"/2.0/repositories/ws/repo/src/new/README.md": "def",
+			},
+			commits: []string{"old"},
+			err:     true,
+		},
+		"branch moved and file created": {
+			parent: "old",
+			rebase: true,
+			files: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/src/new/README.md": "",
+			},
+			commits: []string{"old"},
+			err:     true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			transport := &testMovedBranchTransport{head: "new", files: tc.files}
+			client := &Client{HTTPClient: &http.Client{Transport: transport}}
+
+			commit := srcCommit{
+				message: "Update the readme",
+				branch:  "main",
+				files:   map[string][]byte{"README.md": []byte("ghi")},
+			}
+
+			hash, err := commitFileOnParent(client, "ws", "repo", commit, "README.md", tc.parent, tc.rebase)
+
+			var moved *branchMoved
+			switch {
+			case tc.moved:
+				if !errors.As(err, &moved) {
+					t.Fatalf("expected the branch to have moved, got %v", err)
+				}
+				if moved.expected != "old" || moved.head != "new" {
+					t.Errorf("unexpected error %s", moved)
+				}
+			case tc.err:
+				if err == nil || errors.As(err, &moved) {
+					t.Fatalf("expected a conflict, got %v", err)
+				}
+			case err != nil:
+				t.Fatalf("err: %s", err)
+			case hash != "abc123":
+				t.Errorf("expected hash abc123, got %q", hash)
+			}
+
+			if !reflect.DeepEqual(transport.commits, tc.commits) {
+				t.Errorf("expected commits with parents %q, got %q", tc.commits, transport.commits)
+			}
+		})
+	}
+}
+
+func TestResourceCommitFileRead_drift(t *testing.T) {
+	cases := map[string]struct {
+		content              string
+		remote               string
+		normalizeLineEndings bool
+		expected             string
+	}{
+		"unchanged": {
+			content:  "a\nb\n",
+			remote:   "a\nb\n",
+			expected: "a\nb\n",
+		},
+		"changed on the branch": {
+			content:  "a\nb\n",
+			remote:   "a\nc\n",
+			expected: "a\nc\n",
+		},
+		"line endings changed": {
+			content:  "a\nb\n",
+			remote:   "a\r\nb\r\n",
+			expected: "a\r\nb\r\n",
+		},
+		"line endings changed and normalized": {
+			content:              "a\nb\n",
+			remote:               "a\r\nb\r\n",
+			normalizeLineEndings: true,
+			expected:             "a\nb\n",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			defaultTransport := http.DefaultTransport
+			http.DefaultTransport = listResourceTestTransport{
+				"/2.0/repositories/ws/repo/refs/branches/main":                     `{"name": "main", "target": {"hash": "head"}}`,
+				"/2.0/repositories/ws/repo/src/head/docs/READ%20ME.md?format=meta": `{"path": "docs/READ ME.md", "type": "commit_file", "attributes": ["executable"], "commit": {"hash": "last"}}`,
+				"/2.0/repositories/ws/repo/src/head/docs/READ%20ME.md":             tc.remote,
+			}
+			defer func() { http.DefaultTransport = defaultTransport }()
+
+			clients, err := newClients(providerSettings{Username: "user", Password: "password"})
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			d := resourceCommitFile().Data(&terraform.InstanceState{
+				ID: "ws/repo/main/docs/READ ME.md",
+				Attributes: map[string]string{
+					"workspace":              "ws",
+					"repo_slug":              "repo",
+					"branch":                 "main",
+					"filename":               "docs/READ ME.md",
+					"content":                tc.content,
+					"normalize_line_endings": fmt.Sprintf("%t", tc.normalizeLineEndings),
+					"commit_sha":             "first",
+				},
+			})
+
+			if diags := resourceCommitFileRead(context.Background(), d, clients); diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if got := d.Get("content").(string); got != tc.expected {
+				t.Errorf("expected content %q, got %q", tc.expected, got)
+			}
+			if got := d.Get("last_commit_sha").(string); got != "last" {
+				t.Errorf("expected last_commit_sha last, got %q", got)
+			}
+			if got := d.Get("content_sha256").(string); got != commitFileSHA256([]byte(tc.remote), tc.normalizeLineEndings) {
+				t.Errorf("unexpected content_sha256 %q", got)
+			}
+			if !d.Get("executable").(bool) || d.Get("symlink").(bool) {
+				t.Errorf("expected an executable file, got executable %t and symlink %t", d.Get("executable"), d.Get("symlink"))
+			}
+		})
+	}
+}
+
+func TestResourceCommitFileRead_deleted(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = listResourceTestTransport{
+		"/2.0/repositories/ws/repo/refs/branches/main": `{"name": "main", "target": {"hash": "head"}}`,
+	}
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	clients, err := newClients(providerSettings{Username: "user", Password: "password"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceCommitFile().Data(&terraform.InstanceState{
+		ID: "ws/repo/main/README.md",
+		Attributes: map[string]string{
+			"workspace": "ws",
+			"repo_slug": "repo",
+			"branch":    "main",
+			"filename":  "README.md",
+			"content":   "abc",
+		},
+	})
+
+	if diags := resourceCommitFileRead(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+
+	if d.Id() != "" {
+		t.Errorf("expected the deleted file to be removed from the state, got ID %q", d.Id())
+	}
+}
@@ -0,0 +1,464 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+var commitFilesIdentity = newResourceIdentity("/", "workspace", "repo_slug", "branch")
+
+func resourceCommitFiles() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceCommitFilesPut,
+		ReadWithoutTimeout:   resourceCommitFilesRead,
+		UpdateWithoutTimeout: resourceCommitFilesPut,
+		DeleteWithoutTimeout: resourceCommitFilesDelete,
+		CustomizeDiff:        customizeCommitFilesDiff,
+		Importer: commitFilesIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			// Branches may contain slashes, so prefer the identity when importing by identity.
+			idParts, err := commitFilesIdentity.values(d)
+			if err != nil {
+				idParts = strings.SplitN(d.Id(), "/", 3)
+			}
+			if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/BRANCH", d.Id())
+			}
+			d.SetId(strings.Join(idParts, "/"))
+			d.Set("workspace", idParts[0])
+			d.Set("repo_slug", idParts[1])
+			d.Set("branch", idParts[2])
+			d.Set("delete_on_destroy", true)
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: commitFilesIdentity.identitySchema(),
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"repo_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"branch": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"files": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ExactlyOneOf: []string{"files", "source_dir"},
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The content of the files to commit, by path",
+			},
+			"source_dir": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"files", "source_dir"},
+				Description:  "A local directory to commit the files of",
+			},
+			"source_glob": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "**",
+				RequiredWith: []string{"source_dir"},
+				ValidateFunc: validateGlob,
+				Description:  "The pattern the paths of the files of source_dir must match",
+			},
+			"destination_dir": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"source_dir"},
+				Description:  "The directory of the repository the files of source_dir are committed to",
+			},
+			"executables": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The paths of the files committed as executable files",
+			},
+			"symlinks": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The paths of the files committed as symbolic links to the path in their content",
+			},
+			"commit_message": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"commit_author": commitAuthorSchema(),
+			"delete_on_destroy": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"file_sha256": {
+				Type:     schema.TypeMap,
+				Computed: true,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "The SHA-256 of the content of the files on the branch, by path",
+			},
+			"commit_sha": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"head_sha": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The head of the branch when it was last read, which the next commit expects as parent",
+			},
+		},
+	}
+}
+
+// customizeCommitFilesDiff plans a commit when the content of the files
+// differs from the content on the branch.
+func customizeCommitFilesDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	for _, key := range []string{"files", "source_dir", "source_glob", "destination_dir"} {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("file_sha256")
+		}
+	}
+
+	files, err := commitFilesContent(d)
+	if err != nil {
+		return err
+	}
+
+	hashes := commitFilesSHA256(files)
+	current := d.Get("file_sha256").(map[string]interface{})
+
+	if len(hashes) != len(current) {
+		return d.SetNew("file_sha256", hashes)
+	}
+	for path, hash := range hashes {
+		if current[path] != hash {
+			return d.SetNew("file_sha256", hashes)
+		}
+	}
+
+	return nil
+}
+
+func resourceCommitFilesPut(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	branch := d.Get("branch").(string)
+	id := fmt.Sprintf("%s/%s/%s", workspace, repoSlug, branch)
+
+	files, err := commitFilesContent(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// On creation, the files are committed on top of the current head. Later,
+	// they are committed on top of the head read when planning, so commits
+	// pushed in between are detected instead of overwritten.
+	parent := d.Get("head_sha").(string)
+	if d.IsNewResource() || parent == "" {
+		parent, err = getBranchHash(&client, workspace, repoSlug, branch)
+		if err != nil {
+			return diag.Errorf("error reading branch of Commit Files (%s): %s", id, err)
+		}
+		if parent == "" {
+			return diag.Errorf("error committing files (%s): branch %s does not exist", id, branch)
+		}
+	}
+
+	// Files that were not committed before, such as after an import, may
+	// already be on the branch with the same content.
+	commit, err := newCommitFilesCommit(d, files, func(path string, content []byte) (bool, error) {
+		current, err := getSrcFile(&client, workspace, repoSlug, parent, path)
+		if err != nil {
+			return false, fmt.Errorf("error reading %s of Commit Files (%s): %w", path, id, err)
+		}
+		return current != nil && bytes.Equal(current, content), nil
+	})
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if len(commit.files) == 0 && len(commit.deleted) == 0 {
+		log.Printf("[DEBUG] Files of Commit Files (%s) are up to date, nothing to commit", id)
+	} else {
+		commit.parents = []string{parent}
+
+		commitSha, err := commit.create(&client, workspace, repoSlug)
+		if err != nil {
+			if moved := branchMovedError(&client, workspace, repoSlug, branch, parent); moved != nil {
+				return diag.Errorf("error committing files (%s): %s", id, moved)
+			}
+			return diag.Errorf("error committing files (%s): %s", id, err)
+		}
+
+		d.Set("commit_sha", commitSha)
+	}
+
+	d.SetId(id)
+
+	return resourceCommitFilesRead(ctx, d, m)
+}
+
+func resourceCommitFilesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	branch := d.Get("branch").(string)
+
+	head, err := getBranchHash(&client, workspace, repoSlug, branch)
+	if err != nil {
+		return diag.Errorf("error reading branch of Commit Files (%s): %s", d.Id(), err)
+	}
+
+	if head == "" {
+		log.Printf("[WARN] Branch of Commit Files (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	// Only the files committed before are read, a file missing from the
+	// branch is committed again.
+	hashes := map[string]interface{}{}
+	for path := range d.Get("file_sha256").(map[string]interface{}) {
+		content, err := getSrcFile(&client, workspace, repoSlug, head, path)
+		if err != nil {
+			return diag.Errorf("error reading %s of Commit Files (%s): %s", path, d.Id(), err)
+		}
+		if content == nil {
+			continue
+		}
+
+		hashes[path] = commitFileSHA256(content, false)
+	}
+
+	d.Set("file_sha256", hashes)
+	d.Set("head_sha", head)
+
+	if err := commitFilesIdentity.set(d, workspace, repoSlug, branch); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceCommitFilesDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if !d.Get("delete_on_destroy").(bool) {
+		log.Printf("[DEBUG] Files of Commit Files (%s) are kept in the repository, as delete_on_destroy is not set", d.Id())
+		return nil
+	}
+
+	client := m.(Clients).httpClient
+
+	commit := commitFilesChanges(d.Get("file_sha256").(map[string]interface{}), nil)
+	if len(commit.deleted) == 0 {
+		return nil
+	}
+
+	commit.message = fmt.Sprintf("Delete %s", strings.Join(commit.deleted, ", "))
+	commit.author = d.Get("commit_author").(string)
+	commit.branch = d.Get("branch").(string)
+
+	if _, err := commit.create(&client, d.Get("workspace").(string), d.Get("repo_slug").(string)); err != nil {
+		return diag.Errorf("error deleting files of Commit Files (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// newCommitFilesCommit returns the commit of the files of d whose content
+// differs from the hashes of the previous commit, without the new files that
+// onBranch reports already on the branch with the same content, and with the
+// files whose mode changed.
+func newCommitFilesCommit(d *schema.ResourceData, files map[string][]byte, onBranch func(path string, content []byte) (bool, error)) (srcCommit, error) {
+	previous, _ := d.GetChange("file_sha256")
+	commit := commitFilesChanges(previous.(map[string]interface{}), files)
+
+	for path, content := range commit.files {
+		if _, ok := previous.(map[string]interface{})[path]; ok {
+			continue
+		}
+
+		unchanged, err := onBranch(path, content)
+		if err != nil {
+			return srcCommit{}, err
+		}
+		if unchanged {
+			delete(commit.files, path)
+		}
+	}
+
+	// Bitbucket only changes the mode of the files committed along.
+	if d.HasChanges("executables", "symlinks") {
+		for _, key := range []string{"executables", "symlinks"} {
+			o, n := d.GetChange(key)
+			for _, path := range o.(*schema.Set).Union(n.(*schema.Set)).List() {
+				if content, ok := files[path.(string)]; ok {
+					commit.files[path.(string)] = content
+				}
+			}
+		}
+	}
+
+	executables := d.Get("executables").(*schema.Set)
+	symlinks := d.Get("symlinks").(*schema.Set)
+	for path := range commit.files {
+		if executables.Contains(path) {
+			commit.executables = append(commit.executables, path)
+		}
+		if symlinks.Contains(path) {
+			commit.symlinks = append(commit.symlinks, path)
+		}
+	}
+	sort.Strings(commit.executables)
+	sort.Strings(commit.symlinks)
+
+	commit.message = d.Get("commit_message").(string)
+	commit.author = d.Get("commit_author").(string)
+	commit.branch = d.Get("branch").(string)
+
+	return commit, nil
+}
+
+// commitFilesChanges returns a commit of the files whose content differs from
+// the previous hashes, deleting the files that are no longer committed.
+func commitFilesChanges(previous map[string]interface{}, files map[string][]byte) srcCommit {
+	commit := srcCommit{
+		files: map[string][]byte{},
+	}
+
+	for path, content := range files {
+		if previous[path] != commitFileSHA256(content, false) {
+			commit.files[path] = content
+		}
+	}
+
+	for path := range previous {
+		if _, ok := files[path]; !ok {
+			commit.deleted = append(commit.deleted, path)
+		}
+	}
+	sort.Strings(commit.deleted)
+
+	return commit
+}
+
+func commitFilesSHA256(files map[string][]byte) map[string]interface{} {
+	hashes := make(map[string]interface{}, len(files))
+	for path, content := range files {
+		hashes[path] = commitFileSHA256(content, false)
+	}
+
+	return hashes
+}
+
+// commitFilesContent returns the content of the files to commit by their
+// path in the repository, from either files or source_dir.
+func commitFilesContent(d interface{ Get(string) interface{} }) (map[string][]byte, error) {
+	files := map[string][]byte{}
+
+	if v, ok := d.Get("files").(map[string]interface{}); ok && len(v) > 0 {
+		for path, content := range v {
+			files[path] = []byte(content.(string))
+		}
+		return files, nil
+	}
+
+	dir, _ := d.Get("source_dir").(string)
+	if dir == "" {
+		return files, nil
+	}
+	glob, _ := d.Get("source_glob").(string)
+	destination, _ := d.Get("destination_dir").(string)
+
+	err := filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if !matchGlob(glob, rel) {
+			return nil
+		}
+
+		content, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		files[path.Join(destination, rel)] = content
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading source_dir: %w", err)
+	}
+
+	return files, nil
+}
+
+// matchGlob reports whether the slash separated name matches pattern, where a
+// ** segment matches any number of directories.
+func matchGlob(pattern, name string) bool {
+	return matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchGlobSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchGlobSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
+
+func validateGlob(v interface{}, k string) (ws []string, errors []error) {
+	for _, segment := range strings.Split(v.(string), "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			errors = append(errors, fmt.Errorf("%q is not a valid pattern: %w", k, err))
+			return
+		}
+	}
+	return
+}
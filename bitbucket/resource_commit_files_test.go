@@ -0,0 +1,168 @@
+package bitbucket
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccBitbucketCommitFiles_basic(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	owner := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_commit_files.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketCommitFilesConfig(owner, rName, `{
+    "config/a.json" = "{}"
+    "config/b.json" = "[]"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "file_sha256.%", "2"),
+					resource.TestCheckResourceAttrSet(resourceName, "commit_sha"),
+					resource.TestCheckResourceAttrPair(resourceName, "head_sha", resourceName, "commit_sha"),
+				),
+			},
+			{
+				// Modifying a file and deleting another makes a single commit.
+				Config: testAccBitbucketCommitFilesConfig(owner, rName, `{
+    "config/a.json" = "{\"a\": 1}"
+  }`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "file_sha256.%", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "head_sha", resourceName, "commit_sha"),
+					testAccCheckBitbucketCommitFileDeleted(owner, rName, "main", "config/b.json"),
+				),
+			},
+		},
+	})
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		name     string
+		expected bool
+	}{
+		{"**", "a.json", true},
+		{"**", "config/a.json", true},
+		{"*.json", "a.json", true},
+		{"*.json", "config/a.json", false},
+		{"**/*.json", "a.json", true},
+		{"**/*.json", "config/prod/a.json", true},
+		{"config/**", "config/prod/a.json", true},
+		{"config/**", "other/a.json", false},
+		{"config/**/a.json", "config/a.json", true},
+		{"config/*/a.json", "config/a.json", false},
+		{"*.yaml", "a.json", false},
+	}
+
+	for _, tc := range cases {
+		if got := matchGlob(tc.pattern, tc.name); got != tc.expected {
+			t.Errorf("matchGlob(%q, %q): expected %t, got %t", tc.pattern, tc.name, tc.expected, got)
+		}
+	}
+}
+
+func TestCommitFilesChanges(t *testing.T) {
+	files := map[string][]byte{
+		"same.txt":    []byte("same"),
+		"changed.txt": []byte("new"),
+		"added.txt":   []byte("added"),
+	}
+	previous := map[string]interface{}{
+		"same.txt":      commitFilesSHA256(files)["same.txt"],
+		"changed.txt":   commitFilesSHA256(map[string][]byte{"changed.txt": []byte("old")})["changed.txt"],
+		"removed.txt":   "0000",
+		"a/removed.txt": "0000",
+	}
+
+	commit := commitFilesChanges(previous, files)
+
+	expectedFiles := map[string][]byte{
+		"changed.txt": []byte("new"),
+		"added.txt":   []byte("added"),
+	}
+	if !reflect.DeepEqual(commit.files, expectedFiles) {
+		t.Errorf("expected files %q, got %q", expectedFiles, commit.files)
+	}
+
+	expectedDeleted := []string{"a/removed.txt", "removed.txt"}
+	if !reflect.DeepEqual(commit.deleted, expectedDeleted) {
+		t.Errorf("expected deleted %q, got %q", expectedDeleted, commit.deleted)
+	}
+}
+
+func TestCommitFilesContent_sourceDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"a.json":           "{}",
+		"prod/b.json":      "[]",
+		"prod/notes.txt":   "notes",
+		"prod/eu/c.json":   "1",
+		"prod/eu/d.yaml":   "d: 1",
+		"staging/e.json":   "2",
+		"staging/f.backup": "f",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceCommitFiles().Schema, map[string]interface{}{
+		"workspace":       "ws",
+		"repo_slug":       "repo",
+		"branch":          "main",
+		"commit_message":  "Sync config",
+		"source_dir":      dir,
+		"source_glob":     "prod/**/*.json",
+		"destination_dir": "config",
+	})
+
+	files, err := commitFilesContent(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	expected := map[string][]byte{
+		"config/prod/b.json":    []byte("[]"),
+		"config/prod/eu/c.json": []byte("1"),
+	}
+	if !reflect.DeepEqual(files, expected) {
+		t.Errorf("expected files %q, got %q", expected, files)
+	}
+}
+
+func testAccBitbucketCommitFilesConfig(owner, rName, files string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_commit_files" "test" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = "main"
+  commit_message = "Update config"
+  files          = %[3]s
+}
+`, owner, rName, files)
+}
@@ -27,15 +27,21 @@ type PaginatedReviewers struct {
 	Next   string     `json:"next,omitempty"`
 }
 
+var defaultReviewersIdentity = resourceIdentity{
+	attributes: []string{"workspace", "repo_slug"},
+	importID: func(values []string) string {
+		return fmt.Sprintf("%s/%s/reviewers", values[0], values[1])
+	},
+}
+
 func resourceDefaultReviewers() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceDefaultReviewersCreate,
 		ReadWithoutTimeout:   resourceDefaultReviewersRead,
 		UpdateWithoutTimeout: resourceDefaultReviewersUpdate,
 		DeleteWithoutTimeout: resourceDefaultReviewersDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             defaultReviewersIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             defaultReviewersIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"owner": {
@@ -128,6 +134,10 @@ func resourceDefaultReviewersRead(ctx context.Context, d *schema.ResourceData, m
 	d.Set("repository", repo)
 	d.Set("reviewers", terraformReviewers)
 
+	if err := defaultReviewersIdentity.set(d, owner, repo); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -46,6 +46,8 @@ data "bitbucket_current_user" "test" {}
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_default_reviewers" "test" {
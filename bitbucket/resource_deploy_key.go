@@ -14,15 +14,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+var deployKeyIdentity = newResourceIdentity("/", "workspace", "repo_slug", "key_id")
+
 func resourceDeployKey() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceDeployKeysCreate,
 		ReadWithoutTimeout:   resourceDeployKeysRead,
 		UpdateWithoutTimeout: resourceDeployKeysUpdate,
 		DeleteWithoutTimeout: resourceDeployKeysDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             deployKeyIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             deployKeyIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -126,6 +127,10 @@ func resourceDeployKeysRead(ctx context.Context, d *schema.ResourceData, m inter
 	d.Set("comment", deployKey.Comment)
 	d.Set("key_id", keyId)
 
+	if err := deployKeyIdentity.set(d, workspace, repo, keyId); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -131,6 +131,8 @@ func testAccBitbucketDeployKeyConfig(workspace, rName, pubkey string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_deploy_key" "test" {
@@ -146,6 +148,8 @@ func testAccBitbucketDeployKeyLabelConfig(workspace, rName, pubkey, label string
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_deploy_key" "test" {
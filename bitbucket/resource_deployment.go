@@ -40,15 +40,21 @@ type Change struct {
 	Restrictions Restrictions `json:"restrictions,omitempty"`
 }
 
+var deploymentIdentity = resourceIdentity{
+	attributes: []string{"workspace", "repo_slug", "uuid"},
+	importID: func(values []string) string {
+		return fmt.Sprintf("%s/%s:%s", values[0], values[1], values[2])
+	},
+}
+
 func resourceDeployment() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceDeploymentCreate,
 		UpdateWithoutTimeout: resourceDeploymentUpdate,
 		ReadWithoutTimeout:   resourceDeploymentRead,
 		DeleteWithoutTimeout: resourceDeploymentDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             deploymentIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             deploymentIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"uuid": {
@@ -193,6 +199,15 @@ func resourceDeploymentRead(ctx context.Context, d *schema.ResourceData, m inter
 	d.Set("repository", repoId)
 	d.Set("restrictions", flattenRestrictions(deploy.Restrictions))
 
+	workspace, repoSlug, err := splitFullName(repoId)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := deploymentIdentity.set(d, workspace, repoSlug, deployId); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
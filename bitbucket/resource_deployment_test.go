@@ -145,6 +145,8 @@ func testAccBitbucketDeployment(workspace, repoName, deployName string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_deployment" "test" {
@@ -160,6 +162,8 @@ func testAccBitbucketDeploymentAdmin(workspace, repoName, deployName string, adm
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_deployment" "test" {
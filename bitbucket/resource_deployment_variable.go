@@ -10,26 +10,34 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var deploymentVariableIdentity = resourceIdentity{
+	attributes: []string{"workspace", "repo_slug", "deployment_uuid", "uuid"},
+	importID: func(values []string) string {
+		return fmt.Sprintf("%s/%s:%s/%s", values[0], values[1], values[2], values[3])
+	},
+}
+
 func resourceDeploymentVariable() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceDeploymentVariableCreate,
 		UpdateWithoutTimeout: resourceDeploymentVariableUpdate,
 		ReadWithoutTimeout:   resourceDeploymentVariableRead,
 		DeleteWithoutTimeout: resourceDeploymentVariableDelete,
-		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				idParts := strings.Split(d.Id(), "/")
-				if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected DEPLOYMENT-ID/DEPLOYMENT-VARIABLE-ID", d.Id())
-				}
-				d.SetId(idParts[2])
-				d.Set("deployment", strings.Join([]string{idParts[0], idParts[1]}, "/"))
-				return []*schema.ResourceData{d}, nil
-			},
-		},
+		CustomizeDiff:        customizePipelineVariableDiff,
+		Importer: deploymentVariableIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			idParts := strings.Split(d.Id(), "/")
+			if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected DEPLOYMENT-ID/DEPLOYMENT-VARIABLE-ID", d.Id())
+			}
+			d.SetId(idParts[2])
+			d.Set("deployment", strings.Join([]string{idParts[0], idParts[1]}, "/"))
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: deploymentVariableIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"uuid": {
@@ -41,9 +49,38 @@ func resourceDeploymentVariable() *schema.Resource {
 				Required: true,
 			},
 			"value": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"value", "value_wo"},
+			},
+			"value_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				ExactlyOneOf: []string{"value", "value_wo"},
+				RequiredWith: []string{"value_wo_version"},
+			},
+			"value_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				RequiredWith: []string{"value_wo"},
+			},
+			// Kept in private state, see privateStateAttributes.
+			"value_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"value_in_sync": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"detect_secured_drift": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"rewrite", "warn"}, false),
 			},
 			"secured": {
 				Type:     schema.TypeBool,
@@ -61,7 +98,7 @@ func resourceDeploymentVariable() *schema.Resource {
 func newDeploymentVariableFromResource(d *schema.ResourceData) *bitbucket.DeploymentVariable {
 	dk := &bitbucket.DeploymentVariable{
 		Key:     d.Get("key").(string),
-		Value:   d.Get("value").(string),
+		Value:   pipelineVariableValue(d),
 		Secured: d.Get("secured").(bool),
 	}
 	return dk
@@ -91,6 +128,10 @@ func resourceDeploymentVariableCreate(ctx context.Context, d *schema.ResourceDat
 	d.Set("uuid", rvRes.Uuid)
 	d.SetId(rvRes.Uuid)
 
+	if err := setPipelineVariableValueHash(d, rvcr.Value); err != nil {
+		return diag.FromErr(err)
+	}
+
 	time.Sleep(5000 * time.Millisecond) // sleep for a while, to allow BitBucket cache to catch up
 	return resourceDeploymentVariableRead(ctx, d, m)
 }
@@ -142,13 +183,19 @@ func resourceDeploymentVariableRead(ctx context.Context, d *schema.ResourceData,
 	d.Set("uuid", deployVar.Uuid)
 	d.Set("secured", deployVar.Secured)
 
-	if !deployVar.Secured {
+	if _, ok := d.GetOk("value_wo_version"); ok {
+		d.Set("value", "")
+	} else if !deployVar.Secured {
 		d.Set("value", deployVar.Value)
 	} else {
 		d.Set("value", d.Get("value").(string))
 	}
 
-	return nil
+	if err := deploymentVariableIdentity.set(d, workspace, repoSlug, deployment, d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return checkPipelineVariableDrift(d, deployVar.Secured, deployVar.Value)
 }
 
 func resourceDeploymentVariableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -167,6 +214,10 @@ func resourceDeploymentVariableUpdate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
+	if err := setPipelineVariableValueHash(d, rvcr.Value); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceDeploymentVariableRead(ctx, d, m)
 }
 
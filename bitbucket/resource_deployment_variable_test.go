@@ -163,6 +163,8 @@ func testAccBitbucketDeploymentVariableConfig(owner, rName, val string, secure b
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_deployment" "test" {
@@ -185,6 +187,8 @@ func testAccBitbucketDeploymentVariableManyConfig(owner, rName, val string, secu
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_deployment" "test" {
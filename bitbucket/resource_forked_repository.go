@@ -4,148 +4,225 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"net/http"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/antihax/optional"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var forkedRepositoryIdentity = newResourceIdentity("/", "workspace", "repo_slug")
+
 func resourceForkedRepository() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateContext:        resourceForkedRepositoryCreate,
-		UpdateWithoutTimeout: resourceRepositoryUpdate,
+		UpdateWithoutTimeout: resourceForkedRepositoryUpdate,
 		ReadContext:          resourceForkedRepositoryRead,
 		DeleteWithoutTimeout: resourceRepositoryDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+		Importer:             forkedRepositoryIdentity.importer(importDeletionDefaults(schema.ImportStatePassthroughContext)),
+		Identity:             forkedRepositoryIdentity.identitySchema(),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
 		},
-		Schema: map[string]*schema.Schema{
-			"scm": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"has_wiki": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-			"has_issues": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-			"website": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"clone_ssh": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"clone_https": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"project_key": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-			},
-			"is_private": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
-			},
-			"pipelines_enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-			"fork_policy": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Default:      "allow_forks",
-				ValidateFunc: validation.StringInSlice([]string{"allow_forks", "no_public_forks", "no_forks"}, false),
-			},
-			"language": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"owner": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+		// Renaming or transferring a repository changes its identity.
+		ResourceBehavior: schema.ResourceBehavior{
+			MutableIdentity: true,
+		},
+		CustomizeDiff: customdiff.All(
+			customizeRepositoryDiff,
+			customizeForkSyncDiff,
+		),
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceForkedRepositoryV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceForkedRepositoryStateUpgradeV0,
 			},
-			"slug": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					return computeSlug(old) == computeSlug(new)
+		},
+		Schema: repositorySchema(),
+	}
+
+	resource.Schema["parent"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Required: true,
+		ForceNew: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"workspace": {
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
 				},
-			},
-			"uuid": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"link": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Computed: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"avatar": {
-							Type:     schema.TypeList,
-							Optional: true,
-							MaxItems: 1,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"href": {
-										Type:     schema.TypeString,
-										Optional: true,
-										DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-											return strings.HasPrefix(old, "https://bytebucket.org/ravatar/")
-										},
-									},
-								},
-							},
-						},
-					},
+				"slug": {
+					Type:     schema.TypeString,
+					Required: true,
+					ForceNew: true,
+				},
+				"uuid": {
+					Type:     schema.TypeString,
+					Computed: true,
 				},
 			},
-			"parent": {
-				Type: schema.TypeMap,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+		},
+	}
+
+	resource.Schema["sync_with_parent"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+
+	resource.Schema["mainbranch_hash"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	resource.Schema["parent_mainbranch_hash"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return resource
+}
+
+// repositoryParent is the repository a fork was forked from.
+type repositoryParent struct {
+	workspace string
+	slug      string
+}
+
+func expandRepositoryParent(l []interface{}) *repositoryParent {
+	if len(l) == 0 || l[0] == nil {
+		return nil
+	}
+
+	m := l[0].(map[string]interface{})
+
+	return &repositoryParent{
+		workspace: m["workspace"].(string),
+		slug:      m["slug"].(string),
+	}
+}
+
+// flattenRepositoryParent returns the parent of a fork. The workspace and
+// slug in current are kept when they name the same parent, so a renamed
+// parent or a different case does not replace the fork.
+func flattenRepositoryParent(parent *bitbucket.Repository, current []interface{}) ([]interface{}, error) {
+	workspace, slug, err := splitFullName(parent.FullName)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(current) > 0 && current[0] != nil {
+		m := current[0].(map[string]interface{})
+		sameName := strings.EqualFold(m["workspace"].(string), workspace) && strings.EqualFold(m["slug"].(string), slug)
+		if sameName || (m["uuid"].(string) != "" && m["uuid"].(string) == parent.Uuid) {
+			workspace = m["workspace"].(string)
+			slug = m["slug"].(string)
+		}
+	}
+
+	return []interface{}{
+		map[string]interface{}{
+			"workspace": workspace,
+			"slug":      slug,
+			"uuid":      parent.Uuid,
+		},
+	}, nil
+}
+
+// resourceForkedRepositoryV0 is the schema of forked repositories whose
+// parent was a map of its workspace, or its deprecated owner, and slug.
+func resourceForkedRepositoryV0() *schema.Resource {
+	resource := &schema.Resource{
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: repositorySchema(),
+	}
+
+	resource.Schema["parent"] = &schema.Schema{
+		Type:     schema.TypeMap,
+		Required: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+
+	resource.Schema["parent_repository"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"workspace": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"slug": {
+					Type:     schema.TypeString,
+					Computed: true,
 				},
-				Required: true,
-				ForceNew: true,
-				ValidateFunc: func(val interface{}, key string) (warns []string, errs []error) {
-					v := val.(map[string]interface{})
-					if _, ok := v["slug"]; !ok {
-						errs = append(errs, fmt.Errorf("A repository 'slug' is required when specifying a parent to fork from."))
-					}
-					if _, ok := v["owner"]; !ok {
-						errs = append(errs, fmt.Errorf("A repository 'owner' is required when specifying a parent to fork from."))
-					}
-					return warns, errs
+				"uuid": {
+					Type:     schema.TypeString,
+					Computed: true,
 				},
 			},
 		},
 	}
+
+	for _, key := range []string{"mainbranch_hash", "parent_mainbranch_hash"} {
+		resource.Schema[key] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+
+	resource.Schema["sync_with_parent"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+	}
+
+	return resource
+}
+
+// resourceForkedRepositoryStateUpgradeV0 moves the parent map, and the uuid
+// of the parent_repository read along, into the parent block.
+func resourceForkedRepositoryStateUpgradeV0(ctx context.Context, rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	if rawState == nil {
+		return rawState, nil
+	}
+
+	old, _ := rawState["parent"].(map[string]interface{})
+	workspace, _ := old["workspace"].(string)
+	if workspace == "" {
+		workspace, _ = old["owner"].(string)
+	}
+	slug, _ := old["slug"].(string)
+
+	var uuid string
+	if l, ok := rawState["parent_repository"].([]interface{}); ok && len(l) > 0 {
+		if m, ok := l[0].(map[string]interface{}); ok {
+			uuid, _ = m["uuid"].(string)
+		}
+	}
+	delete(rawState, "parent_repository")
+
+	rawState["parent"] = []interface{}{
+		map[string]interface{}{
+			"workspace": workspace,
+			"slug":      slug,
+			"uuid":      uuid,
+		},
+	}
+
+	return rawState, nil
 }
 
 type forkWorkspace struct {
@@ -158,6 +235,7 @@ type forkedRepositoryBody struct {
 	IsPrivate   bool                       `json:"is_private,omitempty"`
 	Description string                     `json:"description,omitempty"`
 	ForkPolicy  string                     `json:"fork_policy,omitempty"`
+	Website     string                     `json:"website,omitempty"`
 	HasWiki     bool                       `json:"has_wiki,omitempty"`
 	HasIssues   bool                       `json:"has_issues,omitempty"`
 	Links       *bitbucket.RepositoryLinks `json:"links,omitempty"`
@@ -165,13 +243,14 @@ type forkedRepositoryBody struct {
 	Workspace   *forkWorkspace             `json:"workspace,omitempty"`
 }
 
-func createForkedRepositoryFromRepository(repo *bitbucket.Repository, targetWorkspaceSlug string) *forkedRepositoryBody {
+func createForkedRepositoryFromRepository(repo *repositoryBody, targetWorkspaceSlug string) *forkedRepositoryBody {
 	forkedRepo := &forkedRepositoryBody{
 		Name:        repo.Name,
 		Language:    repo.Language,
 		IsPrivate:   repo.IsPrivate,
 		Description: repo.Description,
 		ForkPolicy:  repo.ForkPolicy,
+		Website:     repo.Website,
 		HasWiki:     repo.HasWiki,
 		HasIssues:   repo.HasIssues,
 	}
@@ -193,7 +272,10 @@ func resourceForkedRepositoryCreate(ctx context.Context, d *schema.ResourceData,
 	c := m.(Clients).genClient
 	repoApi := c.ApiClient.RepositoriesApi
 	pipeApi := c.ApiClient.PipelinesApi
-	repo := newRepositoryFromResource(d)
+	repo, err := newRepositoryFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	var repoSlug string
 	repoSlug = d.Get("slug").(string)
@@ -203,14 +285,12 @@ func resourceForkedRepositoryCreate(ctx context.Context, d *schema.ResourceData,
 	repoSlug = computeSlug(repoSlug)
 
 	workspace := d.Get("owner").(string)
-	parent := d.Get("parent").(map[string]interface{})
-	parentRepoSlug := parent["slug"].(string)
-	parentWorkspace := parent["owner"].(string)
+	parent := expandRepositoryParent(d.Get("parent").([]interface{}))
 	requestRepo := createForkedRepositoryFromRepository(repo, workspace)
 	repoBody := &bitbucket.RepositoriesApiRepositoriesWorkspaceRepoSlugForksPostOpts{
 		Body: optional.NewInterface(requestRepo),
 	}
-	_, _, err := repoApi.RepositoriesWorkspaceRepoSlugForksPost(c.AuthContext, parentRepoSlug, parentWorkspace, repoBody)
+	_, _, err = repoApi.RepositoriesWorkspaceRepoSlugForksPost(c.AuthContext, parent.slug, parent.workspace, repoBody)
 	if err := handleClientError(err); err != nil {
 		return diag.FromErr(err)
 	}
@@ -237,89 +317,202 @@ func resourceForkedRepositoryCreate(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(retryErr)
 	}
 
-	return resourceRepositoryRead(ctx, d, m)
+	// Forks start with the main branch of their parent.
+	if v, ok := d.GetOk("mainbranch"); ok {
+		client := m.(Clients).httpClient
+		if err := setRepositoryMainbranch(&client, workspace, repoSlug, v.(string)); err != nil {
+			return diag.Errorf("error setting main branch of Forked Repository (%s): %s", d.Id(), err)
+		}
+	}
+
+	return resourceForkedRepositoryRead(ctx, d, m)
 }
 
 func resourceForkedRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	id := d.Id()
-	if id != "" {
-		idparts := strings.Split(id, "/")
-		if len(idparts) == 2 {
-			d.Set("owner", idparts[0])
-			d.Set("slug", idparts[1])
-		} else {
-			return diag.Errorf("incorrect ID format, should match `owner/slug`")
+	repo, diags := readRepositoryResource(ctx, d, m)
+	if diags.HasError() || repo == nil {
+		return diags
+	}
+
+	client := m.(Clients).httpClient
+
+	d.Set("parent_mainbranch_hash", "")
+	if repo.Parent != nil {
+		parent, err := flattenRepositoryParent(repo.Parent, d.Get("parent").([]interface{}))
+		if err != nil {
+			return diag.Errorf("error reading Forked Repository (%s): %s", d.Id(), err)
+		}
+		d.Set("parent", parent)
+
+		// The parent is synced from its current name.
+		workspace, slug, err := splitFullName(repo.Parent.FullName)
+		if err != nil {
+			return diag.Errorf("error reading Forked Repository (%s): %s", d.Id(), err)
+		}
+
+		if d.Get("sync_with_parent").(bool) {
+			hash, err := getMainbranchHash(&client, workspace, slug)
+			if err != nil {
+				return diag.Errorf("error reading main branch of the parent of Forked Repository (%s): %s", d.Id(), err)
+			}
+			d.Set("parent_mainbranch_hash", hash)
 		}
 	}
 
-	var repoSlug string
-	repoSlug = d.Get("slug").(string)
-	if repoSlug == "" {
-		repoSlug = d.Get("name").(string)
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	repoSlug = computeSlug(repoSlug)
 
-	workspace := d.Get("owner").(string)
-	c := m.(Clients).genClient
-	repoApi := c.ApiClient.RepositoriesApi
-	pipeApi := c.ApiClient.PipelinesApi
+	hash, err := getBranchHash(&client, workspace, repoSlug, d.Get("mainbranch").(string))
+	if err != nil {
+		return diag.Errorf("error reading main branch of Forked Repository (%s): %s", d.Id(), err)
+	}
+	d.Set("mainbranch_hash", hash)
 
-	repoRes, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(c.AuthContext, repoSlug, workspace)
+	return nil
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		log.Printf("[WARN] Repository (%s) not found, removing from state", d.Id())
-		d.SetId("")
-		return nil
+func resourceForkedRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.Get("sync_with_parent").(bool) && d.HasChange("mainbranch_hash") {
+		client := m.(Clients).httpClient
+
+		workspace, repoSlug, err := repositoryId(d.Id())
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		mainbranch, _ := d.GetChange("mainbranch")
+
+		syncCtx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutUpdate))
+		defer cancel()
+
+		if err := syncForkWithParent(syncCtx, &client, workspace, repoSlug, mainbranch.(string), d.Get("mainbranch_hash").(string)); err != nil {
+			return diag.Errorf("error syncing Forked Repository (%s) with its parent: %s", d.Id(), err)
+		}
 	}
 
-	if err := handleClientError(err); err != nil {
-		return diag.FromErr(err)
+	if diags := updateRepository(ctx, d, m); diags.HasError() {
+		return diags
 	}
 
-	d.Set("scm", repoRes.Scm)
-	d.Set("is_private", repoRes.IsPrivate)
-	d.Set("has_wiki", repoRes.HasWiki)
-	d.Set("has_issues", repoRes.HasIssues)
-	d.Set("name", repoRes.Name)
-	d.Set("slug", repoRes.Name)
-	d.Set("language", repoRes.Language)
-	d.Set("fork_policy", repoRes.ForkPolicy)
-	// d.Set("website", repoRes.Website)
-	d.Set("description", repoRes.Description)
-	d.Set("project_key", repoRes.Project.Key)
-	d.Set("uuid", repoRes.Uuid)
-
-	if repoRes.Parent != nil {
-		parentMap := make(map[string]string)
-		parentOwner, parentSlug, splitErr := splitFullName(repoRes.Parent.FullName)
-		if splitErr != nil {
-			return diag.Errorf("error reading forked repository (%s)", d.Get("name").(string))
+	return resourceForkedRepositoryRead(ctx, d, m)
+}
+
+// customizeForkSyncDiff plans a sync when the last refresh found the main
+// branch of the parent moved on from the main branch of the fork. Plans that
+// enable or need a sync fail early when git is not installed.
+func customizeForkSyncDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.Get("sync_with_parent").(bool) {
+		return nil
+	}
+
+	if d.Id() == "" || d.HasChange("sync_with_parent") {
+		if err := checkGitInstalled("sync_with_parent"); err != nil {
+			return err
 		}
-		parentMap["owner"] = parentOwner
-		parentMap["slug"] = parentSlug
-		d.Set("parent", parentMap)
 	}
 
-	for _, cloneURL := range repoRes.Links.Clone {
-		if cloneURL.Name == "https" {
-			d.Set("clone_https", cloneURL.Href)
-		} else {
-			d.Set("clone_ssh", cloneURL.Href)
+	if d.Id() == "" {
+		return nil
+	}
+
+	hash := d.Get("parent_mainbranch_hash").(string)
+	if hash != "" && hash != d.Get("mainbranch_hash").(string) {
+		if err := checkGitInstalled("sync_with_parent"); err != nil {
+			return err
 		}
+
+		log.Printf("[DEBUG] Forked Repository (%s) is behind its parent, planning a sync to %s", d.Id(), hash)
+		return d.SetNew("mainbranch_hash", hash)
 	}
 
-	d.Set("link", flattenLinks(repoRes.Links))
+	return nil
+}
 
-	pipelinesConfigReq, res, err := pipeApi.GetRepositoryPipelineConfig(c.AuthContext, workspace, repoSlug)
-	if err := handleClientError(err); err != nil {
-		return diag.FromErr(err)
+// getMainbranchHash returns the hash of the commit at the head of the main
+// branch of a repository, or an empty string when it has none.
+func getMainbranchHash(client *Client, workspace, repoSlug string) (string, error) {
+	repo, err := getRepository(client, workspace, repoSlug)
+	if err != nil {
+		return "", err
+	}
+	if repo == nil || repo.Mainbranch == nil {
+		return "", nil
+	}
+
+	return getBranchHash(client, workspace, repoSlug, repo.Mainbranch.Name)
+}
+
+// syncForkWithParent fast-forwards the main branch of a fork to hash on the
+// main branch of its parent. Bitbucket has no API for it, so the parent is
+// fetched with the git command and pushed to the fork without force, which
+// git rejects when the fork diverged from its parent.
+func syncForkWithParent(ctx context.Context, client *Client, workspace, repoSlug, mainbranch, hash string) error {
+	if err := checkGitInstalled("sync_with_parent"); err != nil {
+		return err
+	}
+
+	// The parent is fetched by its current name, which follows renames.
+	fork, err := getRepository(client, workspace, repoSlug)
+	if err != nil {
+		return err
+	}
+	if fork == nil || fork.Parent == nil {
+		return fmt.Errorf("%s/%s is not a fork", workspace, repoSlug)
+	}
+	parentWorkspace, parentSlug, err := splitFullName(fork.Parent.FullName)
+	if err != nil {
+		return err
+	}
+
+	parentRepo, err := getRepository(client, parentWorkspace, parentSlug)
+	if err != nil {
+		return err
+	}
+	if parentRepo == nil || parentRepo.Mainbranch == nil {
+		return fmt.Errorf("parent %s/%s has no main branch", parentWorkspace, parentSlug)
+	}
+
+	authorization, err := client.gitAuthorization()
+	if err != nil {
+		return err
+	}
+
+	dir, err := os.MkdirTemp("", "terraform-provider-bitbucket-sync-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := runGit(ctx, dir, nil, "init", "--quiet", "--bare"); err != nil {
+		return err
+	}
+
+	parentURL := fmt.Sprintf("%s%s/%s.git", BitbucketGitEndpoint, parentWorkspace, parentSlug)
+	if _, err := runGit(ctx, dir, gitAuthorizationEnv(parentURL, authorization), "fetch", "--quiet", parentURL, "refs/heads/"+parentRepo.Mainbranch.Name); err != nil {
+		return err
 	}
 
-	if res.StatusCode == 200 {
-		d.Set("pipelines_enabled", pipelinesConfigReq.Enabled)
-	} else if res.StatusCode == http.StatusNotFound {
-		d.Set("pipelines_enabled", false)
+	forkURL := fmt.Sprintf("%s%s/%s.git", BitbucketGitEndpoint, workspace, repoSlug)
+	if _, err := runGit(ctx, dir, gitAuthorizationEnv(forkURL, authorization), "push", "--quiet", forkURL, hash+":refs/heads/"+mainbranch); err != nil {
+		return fmt.Errorf("the main branch %s cannot be fast-forwarded, it may have diverged from its parent: %w", mainbranch, err)
 	}
 
 	return nil
 }
+
+// getBranchHash returns the hash of the commit at the head of branch, or an
+// empty string when the branch does not exist.
+func getBranchHash(client *Client, workspace, repoSlug, branch string) (string, error) {
+	if branch == "" {
+		return "", nil
+	}
+
+	ref, err := getBranch(client, workspace, repoSlug, branch)
+	if err != nil || ref == nil {
+		return "", err
+	}
+
+	return ref.Target.Hash, nil
+}
@@ -1,12 +1,16 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/strollby/bitbucket-go-client"
 )
 
 func TestAccBitbucketForkedRepository_basic(t *testing.T) {
@@ -38,15 +42,17 @@ func TestAccBitbucketForkedRepository_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "link.0.avatar.#", "1"),
 					resource.TestCheckResourceAttrSet(resourceName, "link.0.avatar.0.href"),
 					resource.TestCheckResourceAttrSet(resourceName, "project_key"),
-					resource.TestCheckResourceAttr(resourceName, "parent.%", "2"),
-					resource.TestCheckResourceAttrPair(resourceName, "parent.slug", "bitbucket_repository.test", "slug"),
-					resource.TestCheckResourceAttrPair(resourceName, "parent.owner", "bitbucket_repository.test", "owner"),
+					resource.TestCheckResourceAttr(resourceName, "parent.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "parent.0.slug", "bitbucket_repository.test", "slug"),
+					resource.TestCheckResourceAttrPair(resourceName, "parent.0.workspace", "bitbucket_repository.test", "owner"),
+					resource.TestCheckResourceAttrPair(resourceName, "parent.0.uuid", "bitbucket_repository.test", "uuid"),
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "on_destroy", "sync_with_parent"},
 			},
 		},
 	})
@@ -69,35 +75,237 @@ func TestAccBitbucketForkedRepository_project(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "name", fmt.Sprintf("%s-fork", rName)),
 					resource.TestCheckResourceAttr(resourceName, "owner", testUser),
 					resource.TestCheckResourceAttrPair(resourceName, "project_key", "bitbucket_project.test", "key"),
-					resource.TestCheckResourceAttr(resourceName, "parent.%", "2"),
-					resource.TestCheckResourceAttrPair(resourceName, "parent.slug", "bitbucket_repository.test", "slug"),
-					resource.TestCheckResourceAttrPair(resourceName, "parent.owner", "bitbucket_repository.test", "owner"),
+					resource.TestCheckResourceAttr(resourceName, "parent.#", "1"),
+					resource.TestCheckResourceAttrPair(resourceName, "parent.0.slug", "bitbucket_repository.test", "slug"),
+					resource.TestCheckResourceAttrPair(resourceName, "parent.0.workspace", "bitbucket_repository.test", "owner"),
+					resource.TestCheckResourceAttrPair(resourceName, "parent.0.uuid", "bitbucket_repository.test", "uuid"),
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "on_destroy", "sync_with_parent"},
 			},
 		},
 	})
 }
 
+func TestAccBitbucketForkedRepository_syncWithParent(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	testUser := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_forked_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketForkedRepoSyncConfig(testUser, rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "sync_with_parent", "true"),
+					resource.TestCheckResourceAttrSet(resourceName, "mainbranch_hash"),
+				),
+			},
+			{
+				// The fork is only planned to sync once the parent moved on.
+				Config:             testAccBitbucketForkedRepoSyncConfig(testUser, rName, true),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testAccBitbucketForkedRepoSyncConfig(testUser, rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair(resourceName, "mainbranch_hash", "bitbucket_commit_file.test.0", "commit_sha"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceForkedRepositoryStateUpgradeV0(t *testing.T) {
+	cases := map[string]struct {
+		state    map[string]interface{}
+		expected map[string]interface{}
+	}{
+		"workspace": {
+			state: map[string]interface{}{
+				"parent": map[string]interface{}{"workspace": "ws", "slug": "repo"},
+				"parent_repository": []interface{}{
+					map[string]interface{}{"workspace": "ws", "slug": "renamed", "uuid": "{repo}"},
+				},
+			},
+			expected: map[string]interface{}{"workspace": "ws", "slug": "repo", "uuid": "{repo}"},
+		},
+		"owner": {
+			state: map[string]interface{}{
+				"parent": map[string]interface{}{"owner": "ws", "slug": "repo"},
+			},
+			expected: map[string]interface{}{"workspace": "ws", "slug": "repo", "uuid": ""},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := resourceForkedRepositoryStateUpgradeV0(context.Background(), tc.state, nil)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if _, ok := actual["parent_repository"]; ok {
+				t.Errorf("expected parent_repository to be removed")
+			}
+			if !reflect.DeepEqual(actual["parent"], []interface{}{tc.expected}) {
+				t.Errorf("expected parent %v, got %v", tc.expected, actual["parent"])
+			}
+		})
+	}
+}
+
+func TestFlattenRepositoryParent(t *testing.T) {
+	parent := &bitbucket.Repository{FullName: "ws/renamed", Uuid: "{repo}"}
+
+	cases := map[string]struct {
+		current  []interface{}
+		expected map[string]interface{}
+	}{
+		"import": {
+			expected: map[string]interface{}{"workspace": "ws", "slug": "renamed", "uuid": "{repo}"},
+		},
+		"case": {
+			current:  []interface{}{map[string]interface{}{"workspace": "WS", "slug": "Renamed", "uuid": ""}},
+			expected: map[string]interface{}{"workspace": "WS", "slug": "Renamed", "uuid": "{repo}"},
+		},
+		"renamed": {
+			current:  []interface{}{map[string]interface{}{"workspace": "ws", "slug": "repo", "uuid": "{repo}"}},
+			expected: map[string]interface{}{"workspace": "ws", "slug": "repo", "uuid": "{repo}"},
+		},
+		"other parent": {
+			current:  []interface{}{map[string]interface{}{"workspace": "ws", "slug": "repo", "uuid": "{other}"}},
+			expected: map[string]interface{}{"workspace": "ws", "slug": "renamed", "uuid": "{repo}"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			actual, err := flattenRepositoryParent(parent, tc.current)
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if !reflect.DeepEqual(actual, []interface{}{tc.expected}) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCustomizeForkSyncDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "ws/repo-fork",
+		Attributes: map[string]string{
+			"id":                     "ws/repo-fork",
+			"owner":                  "ws",
+			"name":                   "repo-fork",
+			"slug":                   "repo-fork",
+			"parent.#":               "1",
+			"parent.0.workspace":     "other",
+			"parent.0.slug":          "repo",
+			"parent.0.uuid":          "{repo}",
+			"sync_with_parent":       "true",
+			"mainbranch_hash":        "1111111",
+			"parent_mainbranch_hash": "2222222",
+		},
+	}
+
+	cases := map[string]struct {
+		config   map[string]interface{}
+		noGit    bool
+		expected string
+		err      bool
+	}{
+		"behind": {
+			config:   map[string]interface{}{"sync_with_parent": true},
+			expected: "2222222",
+		},
+		"behind without git": {
+			config: map[string]interface{}{"sync_with_parent": true},
+			noGit:  true,
+			err:    true,
+		},
+		"sync disabled": {
+			config: map[string]interface{}{"sync_with_parent": false},
+		},
+		"sync disabled without git": {
+			config: map[string]interface{}{"sync_with_parent": false},
+			noGit:  true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if tc.noGit {
+				t.Setenv("PATH", t.TempDir())
+			}
+
+			config := map[string]interface{}{
+				"owner":  "ws",
+				"name":   "repo-fork",
+				"parent": []interface{}{map[string]interface{}{"workspace": "other", "slug": "repo"}},
+			}
+			for k, v := range tc.config {
+				config[k] = v
+			}
+
+			// Without a client, the diff must not make any API calls.
+			diff, err := resourceForkedRepository().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error without git")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+
+			if diff != nil && diff.RequiresNew() {
+				t.Errorf("expected the fork not to be replaced")
+			}
+
+			var planned string
+			if diff != nil {
+				if attr, ok := diff.Attributes["mainbranch_hash"]; ok {
+					planned = attr.New
+				}
+			}
+			if planned != tc.expected {
+				t.Errorf("expected mainbranch_hash to be planned as %q, got %q", tc.expected, planned)
+			}
+		})
+	}
+}
+
 func testAccBitbucketForkedRepoConfig(testUser, rName string) string {
 	return fmt.Sprintf(`
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_forked_repository" "test" {
   owner = bitbucket_repository.test.owner
   name  = "%[2]s-fork"
 
-  parent = {
-    slug  = bitbucket_repository.test.slug
-	owner = bitbucket_repository.test.owner
+  parent {
+    workspace = bitbucket_repository.test.owner
+    slug      = bitbucket_repository.test.slug
   }
+
+  deletion_protection = false
 }
 `, testUser, rName)
 }
@@ -108,22 +316,65 @@ resource "bitbucket_project" "test" {
   owner = %[1]q
   name  = %[2]q
   key   = "AAAAAAA"
+
+  deletion_protection = false
 }
 
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
-	
+
 resource "bitbucket_forked_repository" "test" {
   owner       = bitbucket_repository.test.owner
   name        = "%[2]s-fork"
   project_key = bitbucket_project.test.key
 
-  parent = {
-    slug  = bitbucket_repository.test.slug
-	owner = bitbucket_repository.test.owner
-  }  
+  parent {
+    workspace = bitbucket_repository.test.owner
+    slug      = bitbucket_repository.test.slug
+  }
+
+  deletion_protection = false
 }
 `, testUser, rName)
 }
+
+func testAccBitbucketForkedRepoSyncConfig(testUser, rName string, commit bool) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_commit_file" "test" {
+  count = %[3]t ? 1 : 0
+
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  branch         = bitbucket_repository.test.mainbranch
+  filename       = "CHANGELOG.md"
+  content        = "Synced with the fork"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "Add changelog"
+}
+
+resource "bitbucket_forked_repository" "test" {
+  owner            = bitbucket_repository.test.owner
+  name             = "%[2]s-fork"
+  sync_with_parent = true
+
+  parent {
+    workspace = bitbucket_repository.test.owner
+    slug      = bitbucket_repository.test.slug
+  }
+
+  deletion_protection = false
+}
+`, testUser, rName, commit)
+}
@@ -23,15 +23,16 @@ type UserGroup struct {
 	EmailForwardingDisabled bool   `json:"email_forwarding_disabled,omitempty"`
 }
 
+var groupIdentity = newResourceIdentity("/", "workspace", "slug")
+
 func resourceGroup() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceGroupsCreate,
 		ReadWithoutTimeout:   resourceGroupsRead,
 		UpdateWithoutTimeout: resourceGroupsUpdate,
 		DeleteWithoutTimeout: resourceGroupsDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             groupIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             groupIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -139,6 +140,10 @@ func resourceGroupsRead(ctx context.Context, d *schema.ResourceData, m interface
 	d.Set("permission", grp.Permission)
 	d.Set("email_forwarding_disabled", grp.EmailForwardingDisabled)
 
+	if err := groupIdentity.set(d, workspace, slug); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -17,14 +17,15 @@ type UserGroupMembership struct {
 	UUID string `json:"uuid,omitempty"`
 }
 
+var groupMembershipIdentity = newResourceIdentity("/", "workspace", "group_slug", "uuid")
+
 func resourceGroupMembership() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceGroupMembershipsPut,
 		ReadWithoutTimeout:   resourceGroupMembershipsRead,
 		DeleteWithoutTimeout: resourceGroupMembershipsDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             groupMembershipIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             groupMembershipIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -126,6 +127,10 @@ func resourceGroupMembershipsRead(ctx context.Context, d *schema.ResourceData, m
 	d.Set("group_slug", slug)
 	d.Set("uuid", member.UUID)
 
+	if err := groupMembershipIdentity.set(d, workspace, slug, uuid); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
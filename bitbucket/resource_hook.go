@@ -26,24 +26,25 @@ type Hook struct {
 	Events               []string `json:"events,omitempty"`
 }
 
+var hookIdentity = newResourceIdentity("/", "workspace", "repo_slug", "uuid")
+
 func resourceHook() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceHookCreate,
 		ReadWithoutTimeout:   resourceHookRead,
 		UpdateWithoutTimeout: resourceHookUpdate,
 		DeleteWithoutTimeout: resourceHookDelete,
-		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				idParts := strings.Split(d.Id(), "/")
-				if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected OWNER/REPO/HOOK-ID", d.Id())
-				}
-				d.SetId(idParts[2])
-				d.Set("owner", idParts[0])
-				d.Set("repository", idParts[1])
-				return []*schema.ResourceData{d}, nil
-			},
-		},
+		Importer: hookIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			idParts := strings.Split(d.Id(), "/")
+			if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected OWNER/REPO/HOOK-ID", d.Id())
+			}
+			d.SetId(idParts[2])
+			d.Set("owner", idParts[0])
+			d.Set("repository", idParts[1])
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: hookIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"owner": {
@@ -209,6 +210,10 @@ func resourceHookRead(ctx context.Context, d *schema.ResourceData, m interface{}
 		d.Set("events", hook.Events)
 	}
 
+	if err := hookIdentity.set(d, d.Get("owner").(string), d.Get("repository").(string), d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
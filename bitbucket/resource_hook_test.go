@@ -138,6 +138,8 @@ func testAccBitbucketHookConfig(testUser, rName string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 resource "bitbucket_hook" "test" {
   owner                  = %[1]q
@@ -158,6 +160,8 @@ func testAccBitbucketHookConfigUpdated(testUser, rName string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 resource "bitbucket_hook" "test" {
   owner                  = %[1]q
@@ -13,15 +13,16 @@ import (
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var pipelineScheduleIdentity = newResourceIdentity("/", "workspace", "repo_slug", "uuid")
+
 func resourcePipelineSchedule() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourcePipelineScheduleCreate,
 		ReadWithoutTimeout:   resourcePipelineScheduleRead,
 		UpdateWithoutTimeout: resourcePipelineScheduleUpdate,
 		DeleteWithoutTimeout: resourcePipelineScheduleDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             pipelineScheduleIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             pipelineScheduleIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -168,6 +169,10 @@ func resourcePipelineScheduleRead(ctx context.Context, d *schema.ResourceData, m
 
 	d.Set("target", flattenPipelineRefTarget(schedule.Target))
 
+	if err := pipelineScheduleIdentity.set(d, workspace, repo, uuid); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
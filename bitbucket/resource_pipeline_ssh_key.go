@@ -12,15 +12,16 @@ import (
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var pipelineSshKeyIdentity = newResourceIdentity("/", "workspace", "repo_slug")
+
 func resourcePipelineSshKey() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourcePipelineSshKeysPut,
 		ReadWithoutTimeout:   resourcePipelineSshKeysRead,
 		UpdateWithoutTimeout: resourcePipelineSshKeysPut,
 		DeleteWithoutTimeout: resourcePipelineSshKeysDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             pipelineSshKeyIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             pipelineSshKeyIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -90,6 +91,10 @@ func resourcePipelineSshKeysRead(ctx context.Context, d *schema.ResourceData, m
 	d.Set("public_key", key.PublicKey)
 	d.Set("private_key", d.Get("private_key").(string))
 
+	if err := pipelineSshKeyIdentity.set(d, workspace, repo); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
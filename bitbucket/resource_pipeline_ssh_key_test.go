@@ -107,6 +107,8 @@ func testAccBitbucketPipelineSshKeyConfig(workspace, rName, pubKey, privKey stri
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_pipeline_ssh_key" "test" {
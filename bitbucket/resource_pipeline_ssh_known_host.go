@@ -13,15 +13,16 @@ import (
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var pipelineSshKnownHostIdentity = newResourceIdentity("/", "workspace", "repo_slug", "uuid")
+
 func resourcePipelineSshKnownHost() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourcePipelineSshKnownHostsCreate,
 		ReadWithoutTimeout:   resourcePipelineSshKnownHostsRead,
 		UpdateWithoutTimeout: resourcePipelineSshKnownHostsUpdate,
 		DeleteWithoutTimeout: resourcePipelineSshKnownHostsDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             pipelineSshKnownHostIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             pipelineSshKnownHostIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -137,6 +138,10 @@ func resourcePipelineSshKnownHostsRead(ctx context.Context, d *schema.ResourceDa
 	d.Set("uuid", host.Uuid)
 	d.Set("public_key", flattenPipelineSshKnownHost(host.PublicKey))
 
+	if err := pipelineSshKnownHostIdentity.set(d, workspace, repo, uuid); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -111,6 +111,8 @@ func testAccBitbucketPipelineSshKnownHostConfig(workspace, rName, pubKey, host s
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_pipeline_ssh_known_host" "test" {
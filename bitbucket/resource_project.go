@@ -1,7 +1,9 @@
 package bitbucket
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
@@ -13,15 +15,17 @@ import (
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var projectIdentity = newResourceIdentity("/", "workspace", "key")
+
 func resourceProject() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateWithoutTimeout: resourceProjectCreate,
 		UpdateWithoutTimeout: resourceProjectUpdate,
 		ReadWithoutTimeout:   resourceProjectRead,
 		DeleteWithoutTimeout: resourceProjectDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             projectIdentity.importer(importDeletionDefaults(schema.ImportStatePassthroughContext)),
+		Identity:             projectIdentity.identitySchema(),
+		CustomizeDiff:        customizeAvatarDiff,
 
 		Schema: map[string]*schema.Schema{
 			"key": {
@@ -84,9 +88,19 @@ func resourceProject() *schema.Resource {
 			},
 		},
 	}
+
+	for k, v := range deletionSchema() {
+		resource.Schema[k] = v
+	}
+
+	for k, v := range avatarSchema() {
+		resource.Schema[k] = v
+	}
+
+	return resource
 }
 
-func newProjectFromResource(d *schema.ResourceData) *bitbucket.Project {
+func newProjectFromResource(d *schema.ResourceData) (*bitbucket.Project, error) {
 	project := &bitbucket.Project{
 		Name:        d.Get("name").(string),
 		IsPrivate:   d.Get("is_private").(bool),
@@ -98,13 +112,27 @@ func newProjectFromResource(d *schema.ResourceData) *bitbucket.Project {
 		project.Links = expandProjectLinks(v.([]interface{}))
 	}
 
-	return project
+	avatar, err := avatarUpload(d)
+	if err != nil {
+		return nil, err
+	}
+	if avatar != "" {
+		if project.Links == nil {
+			project.Links = &bitbucket.ProjectLinks{}
+		}
+		project.Links.Avatar = &bitbucket.Link{Href: avatar}
+	}
+
+	return project, nil
 }
 
 func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(Clients).genClient
 	projectApi := c.ApiClient.ProjectsApi
-	project := newProjectFromResource(d)
+	project, err := newProjectFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	var projectKey string
 	projectKey = d.Get("key").(string)
@@ -112,9 +140,11 @@ func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interf
 		projectKey = d.Get("key").(string)
 	}
 
-	_, _, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyPut(c.AuthContext, *project, projectKey, d.Get("owner").(string))
-	if err := handleClientError(err); err != nil {
-		return diag.FromErr(err)
+	if d.HasChangesExcept("deletion_protection", "on_destroy", "avatar_file", "avatar_base64") {
+		_, _, err := projectApi.WorkspacesWorkspaceProjectsProjectKeyPut(c.AuthContext, *project, projectKey, d.Get("owner").(string))
+		if err := handleClientError(err); err != nil {
+			return diag.FromErr(err)
+		}
 	}
 
 	return resourceProjectRead(ctx, d, m)
@@ -123,7 +153,10 @@ func resourceProjectUpdate(ctx context.Context, d *schema.ResourceData, m interf
 func resourceProjectCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(Clients).genClient
 	projectApi := c.ApiClient.ProjectsApi
-	project := newProjectFromResource(d)
+	project, err := newProjectFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	var projectKey string
 	projectKey = d.Get("key").(string)
@@ -183,6 +216,13 @@ func resourceProjectRead(ctx context.Context, d *schema.ResourceData, m interfac
 	d.Set("has_publicly_visible_repos", projRes.HasPubliclyVisibleRepos)
 	d.Set("uuid", projRes.Uuid)
 	d.Set("link", flattenProjectLinks(projRes.Links))
+	if projRes.Links != nil && projRes.Links.Avatar != nil {
+		d.Set("avatar_url", projRes.Links.Avatar.Href)
+	}
+
+	if err := projectIdentity.set(d, d.Get("owner").(string), projRes.Key); err != nil {
+		return diag.FromErr(err)
+	}
 
 	return nil
 }
@@ -195,6 +235,21 @@ func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interf
 		projectKey = d.Get("key").(string)
 	}
 
+	if diags := checkDeletionProtection(d, "Project"); diags.HasError() {
+		return diags
+	}
+
+	if d.Get("on_destroy").(string) == onDestroyArchive {
+		client := m.(Clients).httpClient
+
+		if err := archiveProject(&client, d.Get("owner").(string), projectKey, d.Get("name").(string)); err != nil {
+			return diag.Errorf("error archiving Project (%s): %s", d.Id(), err)
+		}
+
+		log.Printf("[INFO] Project (%s) archived, removing from state without deleting it", d.Id())
+		return nil
+	}
+
 	c := m.(Clients).genClient
 	projectApi := c.ApiClient.ProjectsApi
 
@@ -206,6 +261,26 @@ func resourceProjectDelete(ctx context.Context, d *schema.ResourceData, m interf
 	return nil
 }
 
+// archiveProject makes a project private and read-only for everyone given
+// write access to it, keeping its repositories.
+func archiveProject(client *Client, workspace, projectKey, name string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"key":        projectKey,
+		"name":       name,
+		"is_private": true,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Put(fmt.Sprintf("2.0/workspaces/%s/projects/%s", workspace, projectKey), bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+
+	return revokeWritePermissions(client, fmt.Sprintf("2.0/workspaces/%s/projects/%s/permissions-config", workspace, projectKey))
+}
+
 func expandProjectLinks(l []interface{}) *bitbucket.ProjectLinks {
 	if len(l) == 0 || l[0] == nil {
 		return nil
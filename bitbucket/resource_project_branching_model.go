@@ -15,15 +15,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+var projectBranchingModelIdentity = newResourceIdentity("/", "workspace", "project_key")
+
 func resourceProjectBranchingModel() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceProjectBranchingModelsPut,
 		ReadWithoutTimeout:   resourceProjectBranchingModelsRead,
 		UpdateWithoutTimeout: resourceProjectBranchingModelsPut,
 		DeleteWithoutTimeout: resourceProjectBranchingModelsDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             projectBranchingModelIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             projectBranchingModelIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -193,6 +194,10 @@ func resourceProjectBranchingModelsRead(ctx context.Context, d *schema.ResourceD
 	d.Set("branch_type", flattenBranchTypes(branchingModel.BranchTypes))
 	d.Set("production", flattenBranchModel(branchingModel.Production, "production"))
 
+	if err := projectBranchingModelIdentity.set(d, workspace, repo); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
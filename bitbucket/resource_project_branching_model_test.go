@@ -111,6 +111,8 @@ resource "bitbucket_project" "test" {
   owner = %[1]q
   name  = %[2]q
   key   = "DDDDDD"
+
+  deletion_protection = false
 }
 
 resource "bitbucket_project_branching_model" "test" {
@@ -130,6 +132,8 @@ resource "bitbucket_project" "test" {
   owner = %[1]q
   name  = %[2]q
   key   = "EEEEE"
+
+  deletion_protection = false
 }
 
 resource "bitbucket_project_branching_model" "test" {
@@ -154,6 +158,8 @@ resource "bitbucket_project" "test" {
   owner = %[1]q
   name  = %[2]q
   key   = "FFFFF"
+
+  deletion_protection = false
 }
 
 resource "bitbucket_project_branching_model" "test" {
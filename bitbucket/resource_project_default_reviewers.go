@@ -8,20 +8,21 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/strollby/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/strollby/bitbucket-go-client"
 )
 
+var projectDefaultReviewersIdentity = newResourceIdentity("/", "workspace", "project_key")
+
 func resourceProjectDefaultReviewers() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceProjectDefaultReviewersCreate,
 		ReadWithoutTimeout:   resourceProjectDefaultReviewersRead,
 		UpdateWithoutTimeout: resourceProjectDefaultReviewersUpdate,
 		DeleteWithoutTimeout: resourceProjectDefaultReviewersDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             projectDefaultReviewersIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             projectDefaultReviewersIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -115,6 +116,10 @@ func resourceProjectDefaultReviewersRead(ctx context.Context, d *schema.Resource
 	d.Set("project", project)
 	d.Set("reviewers", terraformReviewers)
 
+	if err := projectDefaultReviewersIdentity.set(d, workspace, project); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -47,6 +47,8 @@ resource "bitbucket_project" "test" {
   owner = %[1]q
   name  = %[2]q
   key   = "CCCCCCCC"
+
+  deletion_protection = false
 }
 
 resource "bitbucket_project_default_reviewers" "test" {
@@ -1,7 +1,9 @@
 package bitbucket
 
 import (
+	"encoding/base64"
 	"fmt"
+	"image/color"
 	"net/http"
 	"os"
 	"testing"
@@ -38,9 +40,10 @@ func TestAccBitbucketProject_basic(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "on_destroy"},
 			},
 		},
 	})
@@ -66,9 +69,40 @@ func TestAccBitbucketProject_avatar(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"deletion_protection", "on_destroy"},
+			},
+		},
+	})
+}
+
+func TestAccBitbucketProject_avatarBase64(t *testing.T) {
+	resourceName := "bitbucket_project.test"
+	testTeam := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+	avatar, err := os.ReadFile(testAvatarImage(t, "avatar.png", color.RGBA{G: 255, A: 255}))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketProjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketProjectAvatarBase64Config(testTeam, rName, base64.StdEncoding.EncodeToString(avatar)),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketProjectExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "avatar_sha256", avatarSHA256(avatar)),
+					resource.TestCheckResourceAttrSet(resourceName, "avatar_url"),
+				),
+			},
+			{
+				Config:   testAccBitbucketProjectAvatarBase64Config(testTeam, rName, base64.StdEncoding.EncodeToString(avatar)),
+				PlanOnly: true,
 			},
 		},
 	})
@@ -80,6 +114,8 @@ resource "bitbucket_project" "test" {
   owner = %[1]q
   name  = %[2]q
   key   = "AAAAAA"
+
+  deletion_protection = false
 }
 `, team, rName)
 }
@@ -96,10 +132,25 @@ resource "bitbucket_project" "test" {
       href = "https://d301sr5gafysq2.cloudfront.net/dfb18959be9c/img/repo-avatars/python.png"
 	}
   }
+
+  deletion_protection = false
 }
 `, team, rName)
 }
 
+func testAccBitbucketProjectAvatarBase64Config(team, rName, avatar string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_project" "test" {
+  owner         = %[1]q
+  name          = %[2]q
+  key           = "CCCCCC"
+  avatar_base64 = %[3]q
+
+  deletion_protection = false
+}
+`, team, rName, avatar)
+}
+
 func testAccCheckBitbucketProjectDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(Clients).genClient
 	projectApi := client.ApiClient.ProjectsApi
@@ -10,120 +10,179 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/antihax/optional"
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var repositoryIdentity = newResourceIdentity("/", "workspace", "repo_slug")
+
 func resourceRepository() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateWithoutTimeout: resourceRepositoryCreate,
 		UpdateWithoutTimeout: resourceRepositoryUpdate,
 		ReadWithoutTimeout:   resourceRepositoryRead,
 		DeleteWithoutTimeout: resourceRepositoryDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+		Importer:             repositoryIdentity.importer(importDeletionDefaults(schema.ImportStatePassthroughContext)),
+		Identity:             repositoryIdentity.identitySchema(),
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
 		},
-		Schema: map[string]*schema.Schema{
-			"scm": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Default:      "git",
-				ValidateFunc: validation.StringInSlice([]string{"hg", "git"}, false),
-			},
-			"has_wiki": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-			"has_issues": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-			"website": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"clone_ssh": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"clone_https": {
-				Type:     schema.TypeString,
-				Computed: true,
-			},
-			"project_key": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-			},
-			"is_private": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  true,
-			},
-			"pipelines_enabled": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Default:  false,
-			},
-			"fork_policy": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				Default:      "allow_forks",
-				ValidateFunc: validation.StringInSlice([]string{"allow_forks", "no_public_forks", "no_forks"}, false),
-			},
-			"language": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"description": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"owner": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"name": {
-				Type:     schema.TypeString,
-				Required: true,
-			},
-			"slug": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
-				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-					return computeSlug(old) == computeSlug(new)
+		// Renaming or transferring a repository changes its identity.
+		ResourceBehavior: schema.ResourceBehavior{
+			MutableIdentity: true,
+		},
+		CustomizeDiff: customdiff.All(customizeRepositoryDiff, customizeRepositoryImportDiff),
+		Schema:        repositorySchema(),
+	}
+
+	resource.Schema["initialize"] = &schema.Schema{
+		Type:          schema.TypeBool,
+		Optional:      true,
+		Default:       false,
+		ConflictsWith: []string{"import_source"},
+	}
+
+	resource.Schema["import_source"] = &schema.Schema{
+		Type:          schema.TypeList,
+		Optional:      true,
+		MaxItems:      1,
+		ConflictsWith: []string{"initialize"},
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"url": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
+				},
+				"username": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					RequiredWith: []string{"import_source.0.password"},
+				},
+				"password": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					Sensitive:    true,
+					RequiredWith: []string{"import_source.0.username"},
+				},
+				"mirror": {
+					Type:     schema.TypeBool,
+					Optional: true,
+					Default:  false,
 				},
 			},
-			"uuid": {
-				Type:     schema.TypeString,
-				Computed: true,
+		},
+	}
+
+	return resource
+}
+
+// repositorySchema returns the schema shared by bitbucket_repository and
+// bitbucket_forked_repository.
+func repositorySchema() map[string]*schema.Schema {
+	repositorySchema := map[string]*schema.Schema{
+		"scm": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "git",
+			ValidateFunc: validation.StringInSlice([]string{"hg", "git"}, false),
+		},
+		"has_wiki": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"has_issues": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"website": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"clone_ssh": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"clone_https": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"project_key": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"is_private": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  true,
+		},
+		"pipelines_enabled": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+		"fork_policy": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "allow_forks",
+			ValidateFunc: validation.StringInSlice([]string{"allow_forks", "no_public_forks", "no_forks"}, false),
+		},
+		"language": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"owner": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"slug": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+				return computeSlug(old) == computeSlug(new)
 			},
-			"link": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Computed: true,
-				MaxItems: 1,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"avatar": {
-							Type:     schema.TypeList,
-							Optional: true,
-							MaxItems: 1,
-							Elem: &schema.Resource{
-								Schema: map[string]*schema.Schema{
-									"href": {
-										Type:     schema.TypeString,
-										Optional: true,
-										DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
-											return strings.HasPrefix(old, "https://bytebucket.org/ravatar/")
-										},
+		},
+		"uuid": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"link": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			MaxItems: 1,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"avatar": {
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"href": {
+									Type:     schema.TypeString,
+									Optional: true,
+									DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+										return strings.HasPrefix(old, "https://bytebucket.org/ravatar/")
 									},
 								},
 							},
@@ -131,18 +190,91 @@ func resourceRepository() *schema.Resource {
 					},
 				},
 			},
-			"inherit_default_merge_strategy": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
-			"inherit_branching_model": {
-				Type:     schema.TypeBool,
-				Optional: true,
-				Computed: true,
-			},
 		},
+		"transfer_pending": {
+			Type:     schema.TypeBool,
+			Computed: true,
+		},
+		"mainbranch": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"inherit_default_merge_strategy": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+		"inherit_branching_model": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Computed: true,
+		},
+	}
+
+	for k, v := range deletionSchema() {
+		repositorySchema[k] = v
+	}
+
+	for k, v := range avatarSchema() {
+		repositorySchema[k] = v
+	}
+
+	return repositorySchema
+}
+
+// customizeRepositoryDiff plans the changes of the attributes shared by
+// repositories and forked repositories that Bitbucket computes.
+var customizeRepositoryDiff = customdiff.All(
+	func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+		if d.Id() != "" && d.HasChange("owner") {
+			return d.SetNewComputed("transfer_pending")
+		}
+		return nil
+	},
+	customizeRepositorySlugDiff,
+	customizeAvatarDiff,
+)
+
+// customizeRepositorySlugDiff rejects a change of the slug of an existing
+// repository that does not follow from its name. Bitbucket derives the slug of
+// a repository from its name on update, so any other slug could never be
+// applied.
+func customizeRepositorySlugDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("slug") || !d.NewValueKnown("slug") || !d.NewValueKnown("name") {
+		return nil
+	}
+
+	slug := computeSlug(d.Get("slug").(string))
+	nameSlug := computeSlug(d.Get("name").(string))
+	if !strings.EqualFold(slug, nameSlug) {
+		return fmt.Errorf("the slug of an existing repository can only be changed to the one derived from its name, %q, got %q: "+
+			"change the name to rename the repository, or replace it to use another slug", nameSlug, slug)
 	}
+
+	return nil
+}
+
+// customizeRepositoryImportDiff checks that git is installed before creating a
+// repository with import_source, and rejects a change of the import_source of
+// an existing repository, which is only imported on creation. Removing it is
+// allowed, as there is nothing to undo.
+func customizeRepositoryImportDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	source := d.Get("import_source").([]interface{})
+
+	if d.Id() == "" {
+		if len(source) == 0 {
+			return nil
+		}
+		return checkGitInstalled("import_source")
+	}
+
+	if !d.HasChange("import_source") || len(source) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("import_source of an existing repository cannot be changed, it is only imported on creation: " +
+		"replace the repository to import another source, or ignore the changes of import_source")
 }
 
 type RepositoryInheritanceSettings struct {
@@ -150,22 +282,40 @@ type RepositoryInheritanceSettings struct {
 	BranchingModel       *bool `json:"branching_model,omitempty"`
 }
 
-func newRepositoryFromResource(d *schema.ResourceData) *bitbucket.Repository {
-	repo := &bitbucket.Repository{
-		Name:        d.Get("name").(string),
-		Language:    d.Get("language").(string),
-		IsPrivate:   d.Get("is_private").(bool),
-		Description: d.Get("description").(string),
-		ForkPolicy:  d.Get("fork_policy").(string),
-		HasWiki:     d.Get("has_wiki").(bool),
-		HasIssues:   d.Get("has_issues").(bool),
-		Scm:         d.Get("scm").(string),
+// repositoryBody is a repository as sent to and read from the API, with the
+// fields the generated client model is missing. The website is always sent,
+// so it can be cleared.
+type repositoryBody struct {
+	bitbucket.Repository
+	Website string `json:"website"`
+}
+
+func newRepositoryFromResource(d *schema.ResourceData) (*repositoryBody, error) {
+	repo := &repositoryBody{
+		Repository: bitbucket.Repository{
+			Name:        d.Get("name").(string),
+			Language:    d.Get("language").(string),
+			IsPrivate:   d.Get("is_private").(bool),
+			Description: d.Get("description").(string),
+			ForkPolicy:  d.Get("fork_policy").(string),
+			HasWiki:     d.Get("has_wiki").(bool),
+			HasIssues:   d.Get("has_issues").(bool),
+			Scm:         d.Get("scm").(string),
+		},
+		Website: d.Get("website").(string),
 	}
 
 	if v, ok := d.GetOk("link"); ok && len(v.([]interface{})) > 0 && v.([]interface{}) != nil {
 		repo.Links = expandLinks(v.([]interface{}))
 	}
 
+	if v, ok := d.GetOk("mainbranch"); ok && v.(string) != "" {
+		repo.Mainbranch = &bitbucket.Branch{
+			Type_: "branch",
+			Name:  v.(string),
+		}
+	}
+
 	if v, ok := d.GetOk("project_key"); ok && v.(string) != "" {
 		project := &bitbucket.Project{
 			Key: v.(string),
@@ -173,33 +323,87 @@ func newRepositoryFromResource(d *schema.ResourceData) *bitbucket.Repository {
 		repo.Project = project
 	}
 
-	return repo
+	avatar, err := avatarUpload(d)
+	if err != nil {
+		return nil, err
+	}
+	if avatar != "" {
+		if repo.Links == nil {
+			repo.Links = &bitbucket.RepositoryLinks{}
+		}
+		repo.Links.Avatar = &bitbucket.Link{Href: avatar}
+	}
+
+	return repo, nil
 }
 
 func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if diags := updateRepository(ctx, d, m); diags.HasError() {
+		return diags
+	}
+
+	return resourceRepositoryRead(ctx, d, m)
+}
+
+// updateRepository updates the attributes shared by repositories and forked
+// repositories.
+func updateRepository(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	c := m.(Clients).genClient
 	repoApi := c.ApiClient.RepositoriesApi
 	pipeApi := c.ApiClient.PipelinesApi
 	client := m.(Clients).httpClient
 
-	var repoSlug string
-	repoSlug = d.Get("slug").(string)
-	if repoSlug == "" {
-		repoSlug = d.Get("name").(string)
+	// The slug in the ID is the current one; the slug attribute may already
+	// hold the planned slug of a rename.
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	repoSlug = computeSlug(repoSlug)
-	workspace := d.Get("owner").(string)
 
-	if d.HasChangesExcept("pipelines_enabled", "inherit_default_merge_strategy", "inherit_branching_model") {
-		repository := newRepositoryFromResource(d)
+	transfer := d.HasChange("owner")
+
+	if d.HasChangesExcept("owner", "pipelines_enabled", "inherit_default_merge_strategy", "inherit_branching_model", "deletion_protection", "on_destroy", "initialize", "import_source", "avatar_file", "avatar_base64", "sync_with_parent", "mainbranch_hash") {
+		if d.HasChanges("name", "slug") {
+			if diags := checkRepositorySlugAvailable(d, m, workspace, repoSlug); diags.HasError() {
+				return diags
+			}
+		}
+
+		repository, err := newRepositoryFromResource(d)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if d.HasChange("slug") {
+			repository.Slug = computeSlug(d.Get("slug").(string))
+		}
+		if transfer {
+			// The project key refers to a project of the new workspace,
+			// which can only be set once the transfer is accepted.
+			repository.Project = nil
+		}
 
 		repoBody := &bitbucket.RepositoriesApiRepositoriesWorkspaceRepoSlugPutOpts{
 			Body: optional.NewInterface(repository),
 		}
-		_, _, err := repoApi.RepositoriesWorkspaceRepoSlugPut(c.AuthContext, repoSlug, workspace, repoBody)
+		repoRes, _, err := repoApi.RepositoriesWorkspaceRepoSlugPut(c.AuthContext, repoSlug, workspace, repoBody)
 		if err := handleClientError(err); err != nil {
 			return diag.FromErr(err)
 		}
+
+		// Renaming a repository changes its slug, and every later request
+		// must use the new one.
+		if repoRes.Slug != "" && repoRes.Slug != repoSlug {
+			log.Printf("[DEBUG] Repository (%s) renamed to %s/%s", d.Id(), workspace, repoRes.Slug)
+			repoSlug = repoRes.Slug
+			d.SetId(fmt.Sprintf("%s/%s", workspace, repoSlug))
+		}
+
+		if d.HasChange("project_key") && !transfer {
+			projectKey := d.Get("project_key").(string)
+			if repoRes.Project == nil || repoRes.Project.Key != projectKey {
+				return diag.Errorf("error moving Repository (%s) to project %s: the repository is still in another project", d.Id(), projectKey)
+			}
+		}
 	}
 
 	if d.HasChange("pipelines_enabled") {
@@ -236,7 +440,42 @@ func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, m int
 		}
 	}
 
-	return resourceRepositoryRead(ctx, d, m)
+	if transfer {
+		target := d.Get("owner").(string)
+
+		if target == workspace {
+			// The owner was changed back before the pending transfer was
+			// accepted, so there is nothing left to transfer.
+			d.Set("transfer_pending", false)
+		} else {
+			if err := requestRepositoryTransfer(&client, workspace, repoSlug, target); err != nil {
+				return diag.Errorf("error transferring Repository (%s) to workspace %s: %s", d.Id(), target, err)
+			}
+			d.Set("transfer_pending", true)
+		}
+	}
+
+	return nil
+}
+
+// RepositoryTransfer is a request to transfer a repository to another
+// workspace, which an administrator of that workspace must accept.
+type RepositoryTransfer struct {
+	Workspace string `json:"workspace"`
+}
+
+func requestRepositoryTransfer(client *Client, workspace, repoSlug, target string) error {
+	payload, err := json.Marshal(&RepositoryTransfer{Workspace: target})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Post(fmt.Sprintf("2.0/repositories/%s/%s/transfer",
+		workspace,
+		repoSlug,
+	), bytes.NewBuffer(payload))
+
+	return err
 }
 
 func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -245,7 +484,10 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, m int
 	pipeApi := c.ApiClient.PipelinesApi
 	client := m.(Clients).httpClient
 
-	repo := newRepositoryFromResource(d)
+	repo, err := newRepositoryFromResource(d)
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	var repoSlug string
 	repoSlug = d.Get("slug").(string)
@@ -260,13 +502,45 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, m int
 		Body: optional.NewInterface(repo),
 	}
 
-	_, _, err := repoApi.RepositoriesWorkspaceRepoSlugPost(c.AuthContext, repoSlug, workspace, repoBody)
+	repoRes, _, err := repoApi.RepositoriesWorkspaceRepoSlugPost(c.AuthContext, repoSlug, workspace, repoBody)
 	if err := handleClientError(err); err != nil {
 		return diag.FromErr(err)
 	}
 
 	d.SetId(string(fmt.Sprintf("%s/%s", d.Get("owner").(string), repoSlug)))
 
+	if d.Get("initialize").(bool) {
+		// Bitbucket names the main branch of an empty repository, but the
+		// branch only exists once something is committed to it.
+		branch := d.Get("mainbranch").(string)
+		if branch == "" && repoRes.Mainbranch != nil {
+			branch = repoRes.Mainbranch.Name
+		}
+
+		commit := srcCommit{
+			message: "Initial commit",
+			branch:  branch,
+			files: map[string][]byte{
+				"README.md": []byte(fmt.Sprintf("# %s\n", d.Get("name").(string))),
+			},
+		}
+
+		if _, err := commit.create(&client, workspace, repoSlug); err != nil {
+			return diag.Errorf("error initializing Repository (%s): %s", d.Id(), err)
+		}
+	}
+
+	if v, ok := d.GetOk("import_source"); ok {
+		source := expandRepositoryImport(v.([]interface{}))
+
+		ctx, cancel := context.WithTimeout(ctx, d.Timeout(schema.TimeoutCreate))
+		defer cancel()
+
+		if err := source.run(ctx, &client, workspace, repoSlug, d.Get("mainbranch").(string)); err != nil {
+			return diag.Errorf("error importing Repository (%s) from %s: %s", d.Id(), source.redactedURL(), err)
+		}
+	}
+
 	// nolint:staticcheck
 	if v, ok := d.GetOkExists("pipelines_enabled"); ok {
 		pipelinesConfig := &bitbucket.PipelinesConfig{Enabled: v.(bool)}
@@ -305,14 +579,17 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, m int
 }
 
 func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	c := m.(Clients).genClient
-	repoApi := c.ApiClient.RepositoriesApi
-	pipeApi := c.ApiClient.PipelinesApi
-	client := m.(Clients).httpClient
+	_, diags := readRepositoryResource(ctx, d, m)
+	return diags
+}
 
+// readRepositoryResource reads a repository or forked repository resource,
+// following a pending transfer. It returns the repository read, or nil when
+// it no longer exists.
+func readRepositoryResource(ctx context.Context, d *schema.ResourceData, m interface{}) (*repositoryBody, diag.Diagnostics) {
 	workspace, repoSlug, err := repositoryId(d.Id())
 	if err != nil {
-		diag.FromErr(err)
+		return nil, diag.FromErr(err)
 	}
 
 	if repoSlug == "" {
@@ -320,45 +597,74 @@ func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m inter
 	}
 	repoSlug = computeSlug(repoSlug)
 
-	repoRes, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(c.AuthContext, repoSlug, workspace)
+	// While a transfer is pending the repository stays in its old
+	// workspace, until the new workspace accepts it.
+	target := d.Get("owner").(string)
+	if d.Get("transfer_pending").(bool) && target != "" && target != workspace {
+		repo, diags := readRepository(ctx, d, m, target, repoSlug)
+		if diags.HasError() {
+			return nil, diags
+		}
+
+		if repo != nil {
+			log.Printf("[DEBUG] Repository (%s) transferred to workspace %s", d.Id(), target)
+			workspace = target
+			d.SetId(fmt.Sprintf("%s/%s", workspace, repoSlug))
+			d.Set("transfer_pending", false)
 
-	if res.StatusCode == http.StatusNotFound {
+			if err := repositoryIdentity.set(d, workspace, repoSlug); err != nil {
+				return nil, diag.FromErr(err)
+			}
+
+			return repo, nil
+		}
+	}
+
+	repo, diags := readRepository(ctx, d, m, workspace, repoSlug)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if repo == nil {
 		log.Printf("[WARN] Repository (%s) not found, removing from state", d.Id())
 		d.SetId("")
-		return nil
+		return nil, nil
 	}
 
-	if err := handleClientError(err); err != nil {
-		return diag.FromErr(err)
+	if d.Get("transfer_pending").(bool) {
+		d.Set("owner", target)
 	}
 
-	d.Set("owner", workspace)
-	d.Set("scm", repoRes.Scm)
-	d.Set("is_private", repoRes.IsPrivate)
-	d.Set("has_wiki", repoRes.HasWiki)
-	d.Set("has_issues", repoRes.HasIssues)
-	d.Set("name", repoRes.Name)
-	d.Set("slug", repoRes.Slug)
-	d.Set("language", repoRes.Language)
-	d.Set("fork_policy", repoRes.ForkPolicy)
-	// d.Set("website", repoRes.Website)
-	d.Set("description", repoRes.Description)
-	d.Set("project_key", repoRes.Project.Key)
-	d.Set("uuid", repoRes.Uuid)
-
-	for _, cloneURL := range repoRes.Links.Clone {
-		if cloneURL.Name == "https" {
-			d.Set("clone_https", cloneURL.Href)
-		} else {
-			d.Set("clone_ssh", cloneURL.Href)
-		}
+	// Forked repositories share the identity attributes of repositories.
+	if err := repositoryIdentity.set(d, workspace, repoSlug); err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	return repo, nil
+}
+
+// readRepository reads the repository, its pipelines config and its
+// override settings into d. It returns the repository read, or nil when it
+// does not exist.
+func readRepository(ctx context.Context, d *schema.ResourceData, m interface{}, workspace, repoSlug string) (*repositoryBody, diag.Diagnostics) {
+	c := m.(Clients).genClient
+	pipeApi := c.ApiClient.PipelinesApi
+	client := m.(Clients).httpClient
+
+	repoRes, err := getRepository(&client, workspace, repoSlug)
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	if repoRes == nil {
+		return nil, nil
 	}
 
-	d.Set("link", flattenLinks(repoRes.Links))
+	flattenRepository(d, workspace, repoRes)
 
 	pipelinesConfigReq, res, err := pipeApi.GetRepositoryPipelineConfig(c.AuthContext, workspace, repoSlug)
 	if err := handleClientError(err); err != nil && res.StatusCode != http.StatusNotFound {
-		return diag.FromErr(err)
+		return nil, diag.FromErr(err)
 	}
 
 	if res.StatusCode == 200 {
@@ -373,21 +679,21 @@ func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m inter
 	))
 
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, diag.FromErr(err)
 	}
 
 	var setting RepositoryInheritanceSettings
 
 	body, readerr := io.ReadAll(settingReq.Body)
 	if readerr != nil {
-		return diag.FromErr(readerr)
+		return nil, diag.FromErr(readerr)
 	}
 
 	log.Printf("Repository Inheritance Settings raw is: %#v", string(body))
 
 	decodeerr := json.Unmarshal(body, &setting)
 	if decodeerr != nil {
-		return diag.FromErr(decodeerr)
+		return nil, diag.FromErr(decodeerr)
 	}
 
 	log.Printf("Repository Inheritance Settings decoded is: %#v", setting)
@@ -395,25 +701,175 @@ func resourceRepositoryRead(ctx context.Context, d *schema.ResourceData, m inter
 	d.Set("inherit_default_merge_strategy", setting.DefaultMergeStrategy)
 	d.Set("inherit_branching_model", setting.BranchingModel)
 
-	return nil
+	return repoRes, nil
+}
+
+// flattenRepository sets the attributes shared by repositories and forked
+// repositories from repo.
+func flattenRepository(d *schema.ResourceData, workspace string, repo *repositoryBody) {
+	d.Set("owner", workspace)
+	d.Set("scm", repo.Scm)
+	d.Set("is_private", repo.IsPrivate)
+	d.Set("has_wiki", repo.HasWiki)
+	d.Set("has_issues", repo.HasIssues)
+	d.Set("name", repo.Name)
+	d.Set("slug", repo.Slug)
+	d.Set("language", repo.Language)
+	d.Set("fork_policy", repo.ForkPolicy)
+	d.Set("website", repo.Website)
+	d.Set("description", repo.Description)
+	d.Set("uuid", repo.Uuid)
+
+	// Repositories of personal workspaces have no project.
+	if repo.Project != nil {
+		d.Set("project_key", repo.Project.Key)
+	} else {
+		d.Set("project_key", "")
+	}
+
+	if repo.Mainbranch != nil {
+		d.Set("mainbranch", repo.Mainbranch.Name)
+	} else {
+		d.Set("mainbranch", nil)
+	}
+
+	if repo.Links != nil {
+		for _, cloneURL := range repo.Links.Clone {
+			if cloneURL.Name == "https" {
+				d.Set("clone_https", cloneURL.Href)
+			} else {
+				d.Set("clone_ssh", cloneURL.Href)
+			}
+		}
+	}
+
+	d.Set("link", flattenLinks(repo.Links))
+	if repo.Links != nil && repo.Links.Avatar != nil {
+		d.Set("avatar_url", repo.Links.Avatar.Href)
+	}
+}
+
+// setRepositoryMainbranch changes the main branch of a repository, which must
+// already exist.
+func setRepositoryMainbranch(client *Client, workspace, repoSlug, mainbranch string) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"mainbranch": &bitbucket.Branch{
+			Type_: "branch",
+			Name:  mainbranch,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s", workspace, repoSlug), bytes.NewBuffer(payload))
+	return err
+}
+
+// getRepository reads a repository, including the fields the generated client
+// model is missing. It returns nil when the repository does not exist.
+func getRepository(client *Client, workspace, repoSlug string) (*repositoryBody, error) {
+	res, err := client.Get(fmt.Sprintf("2.0/repositories/%s/%s", workspace, repoSlug))
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var repo repositoryBody
+	if err := json.NewDecoder(res.Body).Decode(&repo); err != nil {
+		return nil, err
+	}
+
+	return &repo, nil
 }
 
 func resourceRepositoryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	workspace, repoSlug, err := repositoryId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	repoSlug = computeSlug(repoSlug)
 
-	var repoSlug string
-	repoSlug = d.Get("slug").(string)
-	if repoSlug == "" {
-		repoSlug = d.Get("name").(string)
+	if diags := checkDeletionProtection(d, "Repository"); diags.HasError() {
+		return diags
 	}
 
+	if d.Get("on_destroy").(string) == onDestroyArchive {
+		client := m.(Clients).httpClient
+
+		if err := archiveRepository(&client, workspace, repoSlug); err != nil {
+			return diag.Errorf("error archiving Repository (%s): %s", d.Id(), err)
+		}
+
+		log.Printf("[INFO] Repository (%s) archived, removing from state without deleting it", d.Id())
+		return nil
+	}
+
+	c := m.(Clients).genClient
+	repoApi := c.ApiClient.RepositoriesApi
+
+	_, err = repoApi.RepositoriesWorkspaceRepoSlugDelete(c.AuthContext, repoSlug, workspace, nil)
+	if err := handleClientError(err); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+// archiveRepository makes a repository private and read-only for everyone
+// given write access to it, keeping its history.
+func archiveRepository(client *Client, workspace, repoSlug string) error {
+	payload, err := json.Marshal(map[string]interface{}{"is_private": true})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.Put(fmt.Sprintf("2.0/repositories/%s/%s", workspace, repoSlug), bytes.NewBuffer(payload))
+	if err != nil {
+		return err
+	}
+
+	return revokeWritePermissions(client, fmt.Sprintf("2.0/repositories/%s/%s/permissions-config", workspace, repoSlug))
+}
+
+// checkRepositorySlugAvailable returns an error when renaming the repository
+// would give it the slug of another repository in the workspace, which
+// Bitbucket otherwise rejects with a less helpful error.
+func checkRepositorySlugAvailable(d *schema.ResourceData, m interface{}, workspace, repoSlug string) diag.Diagnostics {
 	c := m.(Clients).genClient
 	repoApi := c.ApiClient.RepositoriesApi
 
-	_, err := repoApi.RepositoriesWorkspaceRepoSlugDelete(c.AuthContext, repoSlug, d.Get("owner").(string), nil)
+	newSlug := d.Get("slug").(string)
+	if d.HasChange("name") && !d.HasChange("slug") {
+		newSlug = d.Get("name").(string)
+	}
+	newSlug = computeSlug(newSlug)
+
+	if newSlug == repoSlug {
+		return nil
+	}
+
+	existing, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(c.AuthContext, newSlug, workspace)
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil
+	}
 	if err := handleClientError(err); err != nil {
 		return diag.FromErr(err)
 	}
 
+	if existing.Uuid != d.Get("uuid").(string) {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Repository slug already in use",
+			Detail:        fmt.Sprintf("Cannot rename repository %s: the new slug %q is already used by repository %s in workspace %s.", d.Id(), newSlug, existing.FullName, workspace),
+			AttributePath: cty.GetAttrPath("name"),
+		}}
+	}
+
 	return nil
 }
 
@@ -10,10 +10,10 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/strollby/bitbucket-go-client"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strollby/bitbucket-go-client"
 )
 
 type RepositoryGroupPermission struct {
@@ -27,15 +27,16 @@ type RepositoryGroup struct {
 	Workspace bitbucket.Workspace `json:"workspace,omitempty"`
 }
 
+var repositoryGroupPermissionIdentity = newResourceIdentity(":", "workspace", "repo_slug", "group_slug")
+
 func resourceRepositoryGroupPermission() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceRepositoryGroupPermissionPut,
 		ReadWithoutTimeout:   resourceRepositoryGroupPermissionRead,
 		UpdateWithoutTimeout: resourceRepositoryGroupPermissionPut,
 		DeleteWithoutTimeout: resourceRepositoryGroupPermissionDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             repositoryGroupPermissionIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             repositoryGroupPermissionIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -156,6 +157,10 @@ func resourceRepositoryGroupPermissionRead(ctx context.Context, d *schema.Resour
 	d.Set("workspace", permission.Group.Workspace.Slug)
 	d.Set("repo_slug", repoSlug)
 
+	if err := repositoryGroupPermissionIdentity.set(d, workspace, repoSlug, groupSlug); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
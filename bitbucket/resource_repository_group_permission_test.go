@@ -90,6 +90,8 @@ func testAccBitbucketRepositoryGroupPermissionConfig(workspace, rName, permissio
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_group" "test" {
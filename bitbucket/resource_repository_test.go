@@ -1,13 +1,18 @@
 package bitbucket
 
 import (
+	"context"
 	"fmt"
+	"image/color"
 	"net/http"
 	"os"
+	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
@@ -80,6 +85,68 @@ func TestBitbucketRepository_ComputeSlug(t *testing.T) {
 	}
 }
 
+func TestFlattenRepository_withoutProjectOrLinks(t *testing.T) {
+	for name, r := range map[string]*schema.Resource{"resource": resourceRepository(), "data source": dataRepository()} {
+		d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{})
+
+		repo := &repositoryBody{}
+		repo.Name = "personal"
+		repo.Slug = "personal"
+
+		flattenRepository(d, "workspace", repo)
+
+		if got := d.Get("project_key").(string); got != "" {
+			t.Errorf("%s: expected no project_key, got %q", name, got)
+		}
+		if got := d.Get("clone_https").(string); got != "" {
+			t.Errorf("%s: expected no clone_https, got %q", name, got)
+		}
+	}
+}
+
+func TestCustomizeRepositorySlugDiff(t *testing.T) {
+	state := &terraform.InstanceState{
+		ID: "ws/repo-custom",
+		Attributes: map[string]string{
+			"id":    "ws/repo-custom",
+			"owner": "ws",
+			"name":  "repo",
+			"slug":  "repo-custom",
+		},
+	}
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"unchanged": {
+			config: map[string]interface{}{"owner": "ws", "name": "repo", "slug": "repo-custom"},
+		},
+		"slug derived from the name": {
+			config: map[string]interface{}{"owner": "ws", "name": "repo", "slug": "repo"},
+		},
+		"slug derived from the new name": {
+			config: map[string]interface{}{"owner": "ws", "name": "Renamed", "slug": "renamed"},
+		},
+		"other slug": {
+			config: map[string]interface{}{"owner": "ws", "name": "repo", "slug": "repo-other"},
+			err:    `can only be changed to the one derived from its name, "repo", got "repo-other"`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := resourceRepository().Diff(context.Background(), state, terraform.NewResourceConfigRaw(tc.config), nil)
+			switch {
+			case tc.err == "" && err != nil:
+				t.Fatalf("err: %s", err)
+			case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("expected error %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
 func TestAccBitbucketRepository_basic(t *testing.T) {
 	rName := acctest.RandomWithPrefix("tf-test")
 	workspace := os.Getenv("BITBUCKET_TEAM")
@@ -114,9 +181,10 @@ func TestAccBitbucketRepository_basic(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
 			},
 		},
 	})
@@ -142,9 +210,10 @@ func TestAccBitbucketRepository_project(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
 			},
 		},
 	})
@@ -170,9 +239,94 @@ func TestAccBitbucketRepository_avatar(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_avatarFile(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_repository.test"
+	red := testAvatarImage(t, "red.png", color.RGBA{R: 255, A: 255})
+	redCopy := testAvatarImage(t, "red-copy.png", color.RGBA{R: 255, A: 255})
+	blue := testAvatarImage(t, "blue.png", color.RGBA{B: 255, A: 255})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoAvatarFileConfig(workspace, rName, red),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttrSet(resourceName, "avatar_sha256"),
+					resource.TestCheckResourceAttrSet(resourceName, "avatar_url"),
+				),
+			},
+			{
+				Config:   testAccBitbucketRepoAvatarFileConfig(workspace, rName, red),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccBitbucketRepoAvatarFileConfig(workspace, rName, redCopy),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "avatar_file", redCopy),
+				),
+			},
+			{
+				Config: testAccBitbucketRepoAvatarFileConfig(workspace, rName, blue),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(resourceName, "avatar_url"),
+				),
+			},
+			{
+				Config:   testAccBitbucketRepoAvatarFileConfig(workspace, rName, blue),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_website(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoWebsiteConfig(workspace, rName, "https://example.com"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "website", "https://example.com"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
+			},
+			{
+				Config: testAccBitbucketRepoWebsiteConfig(workspace, rName, "https://example.org"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "website", "https://example.org"),
+				),
+			},
+			{
+				Config: testAccBitbucketRepoConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "website", ""),
+				),
 			},
 		},
 	})
@@ -198,9 +352,294 @@ func TestAccBitbucketRepository_slug(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_mainbranch(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoMainbranchConfig(workspace, rName, "develop"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "mainbranch", "develop"),
+					resource.TestCheckResourceAttr(resourceName, "initialize", "true"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
+			},
+			{
+				Config: testAccBitbucketRepoMainbranchConfig(workspace, rName, "release"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "mainbranch", "release"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_rename(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	rNewName := acctest.RandomWithPrefix("tf-test-renamed")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "slug", rName),
+				),
+			},
+			{
+				Config: testAccBitbucketRepoConfig(workspace, rNewName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", fmt.Sprintf("%s/%s", workspace, rNewName)),
+					resource.TestCheckResourceAttr(resourceName, "name", rNewName),
+					resource.TestCheckResourceAttr(resourceName, "slug", rNewName),
+				),
+			},
+			{
+				Config:   testAccBitbucketRepoConfig(workspace, rNewName),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_slugRename(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoSlugConfig(workspace, rName, rName+"-custom"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", fmt.Sprintf("%s/%s-custom", workspace, rName)),
+					resource.TestCheckResourceAttr(resourceName, "slug", rName+"-custom"),
+				),
+			},
+			{
+				Config:      testAccBitbucketRepoSlugConfig(workspace, rName, rName+"-other"),
+				ExpectError: regexp.MustCompile("can only be changed to the one derived from its name"),
+			},
+			{
+				Config: testAccBitbucketRepoSlugConfig(workspace, rName, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "id", fmt.Sprintf("%s/%s", workspace, rName)),
+					resource.TestCheckResourceAttr(resourceName, "name", rName),
+					resource.TestCheckResourceAttr(resourceName, "slug", rName),
+				),
+			},
+			{
+				Config:   testAccBitbucketRepoSlugConfig(workspace, rName, rName),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_renameCollision(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	rOtherName := acctest.RandomWithPrefix("tf-test-other")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoRenameCollisionConfig(workspace, rName, rOtherName),
+			},
+			{
+				Config:      testAccBitbucketRepoRenameCollisionConfig(workspace, rName, rName),
+				ExpectError: regexp.MustCompile("Repository slug already in use"),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_moveProject(t *testing.T) {
+	var uuid string
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoMoveProjectConfig(workspace, rName, "first"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "project_key", "bitbucket_project.first", "key"),
+					testAccCheckBitbucketRepositoryUUID(resourceName, &uuid),
+				),
+			},
+			{
+				Config: testAccBitbucketRepoMoveProjectConfig(workspace, rName, "second"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttrPair(resourceName, "project_key", "bitbucket_project.second", "key"),
+					resource.TestCheckResourceAttrPtr(resourceName, "uuid", &uuid),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_transfer(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	target := os.Getenv("BITBUCKET_TRANSFER_TEAM")
+	resourceName := "bitbucket_repository.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			testAccPreCheckTransferWorkspace(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoConfig(workspace, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "transfer_pending", "false"),
+				),
+			},
+			{
+				Config: testAccBitbucketRepoConfig(target, rName),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "owner", target),
+					resource.TestCheckResourceAttrSet(resourceName, "transfer_pending"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_importSource(t *testing.T) {
+	resourceName := "bitbucket_repository.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+	server := testGitSourceServer(t, "user", "secret")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoImportSourceConfig(workspace, rName, server.URL),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "mainbranch", "develop"),
+					resource.TestCheckResourceAttr(resourceName, "import_source.#", "1"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "import_source", "deletion_protection", "on_destroy"},
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_deletionProtection(t *testing.T) {
+	resourceName := "bitbucket_repository.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoDeletionConfig(workspace, rName, true, "delete"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "deletion_protection", "true"),
+					resource.TestCheckResourceAttr(resourceName, "on_destroy", "delete"),
+				),
+			},
+			{
+				Config:      testAccBitbucketRepoDeletionConfig(workspace, rName, true, "delete"),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Repository is protected from deletion"),
+			},
+			{
+				Config: testAccBitbucketRepoDeletionConfig(workspace, rName, false, "delete"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccBitbucketRepository_archive(t *testing.T) {
+	resourceName := "bitbucket_repository.test"
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketRepositoryArchived,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepoDeletionConfig(workspace, rName, true, "archive"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "on_destroy", "archive"),
+				),
 			},
 		},
 	})
@@ -226,9 +665,10 @@ func TestAccBitbucketRepository_inherit(t *testing.T) {
 				),
 			},
 			{
-				ResourceName:      resourceName,
-				ImportState:       true,
-				ImportStateVerify: true,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"initialize", "deletion_protection", "on_destroy"},
 			},
 			{
 				Config: testAccBitbucketRepoInheritConfig(workspace, rName, false),
@@ -258,6 +698,8 @@ resource "bitbucket_repository" "test" {
   owner                          = %[1]q
   name                           = %[2]q
   inherit_default_merge_strategy = %[3]t
+
+  deletion_protection = false
 }
 `, workspace, rName, enable)
 }
@@ -267,6 +709,8 @@ func testAccBitbucketRepoConfig(workspace, rName string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 `, workspace, rName)
 }
@@ -277,12 +721,16 @@ resource "bitbucket_project" "test" {
   owner = %[1]q
   name  = %[2]q
   key   = "AAAAAAA"
+
+  deletion_protection = false
 }
 	
 resource "bitbucket_repository" "test" {
   owner       = %[1]q
   name        = %[2]q
   project_key = bitbucket_project.test.key
+
+  deletion_protection = false
 }
 `, workspace, rName)
 }
@@ -298,6 +746,8 @@ resource "bitbucket_repository" "test" {
       href = "https://d301sr5gafysq2.cloudfront.net/dfb18959be9c/img/repo-avatars/python.png"
 	}
   }  
+
+  deletion_protection = false
 }
 `, workspace, rName)
 }
@@ -308,6 +758,8 @@ resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
   slug  = %[3]q
+
+  deletion_protection = false
 }
 `, workspace, rName, rSlug)
 }
@@ -320,8 +772,12 @@ func testAccCheckBitbucketRepositoryDestroy(s *terraform.State) error {
 		if rs.Type != "bitbucket_repository" {
 			continue
 		}
-		_, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(client.AuthContext,
-			rs.Primary.Attributes["name"], rs.Primary.Attributes["owner"])
+		workspace, repoSlug, err := repositoryId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		_, res, err := repoApi.RepositoriesWorkspaceRepoSlugGet(client.AuthContext, repoSlug, workspace)
 
 		if err == nil {
 			return fmt.Errorf("The repository was found should have errored")
@@ -334,6 +790,38 @@ func testAccCheckBitbucketRepositoryDestroy(s *terraform.State) error {
 	return nil
 }
 
+// testAccCheckBitbucketRepositoryArchived checks archived repositories were
+// kept as private repositories, then deletes them.
+func testAccCheckBitbucketRepositoryArchived(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).genClient
+	repoApi := client.ApiClient.RepositoriesApi
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_repository" {
+			continue
+		}
+		workspace, repoSlug, err := repositoryId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		repo, _, err := repoApi.RepositoriesWorkspaceRepoSlugGet(client.AuthContext, repoSlug, workspace)
+		if err != nil {
+			return fmt.Errorf("The archived repository was not found: %s", err)
+		}
+
+		if !repo.IsPrivate {
+			return fmt.Errorf("The archived repository is not private")
+		}
+
+		_, err = repoApi.RepositoriesWorkspaceRepoSlugDelete(client.AuthContext, repoSlug, workspace, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func testAccCheckBitbucketRepositoryExists(n string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[n]
@@ -346,3 +834,136 @@ func testAccCheckBitbucketRepositoryExists(n string) resource.TestCheckFunc {
 		return nil
 	}
 }
+
+func testAccCheckBitbucketRepositoryUUID(n string, uuid *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+		*uuid = rs.Primary.Attributes["uuid"]
+		return nil
+	}
+}
+
+func testAccBitbucketRepoMainbranchConfig(workspace, rName, mainbranch string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  mainbranch = %[3]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_commit_file" "release" {
+  workspace      = bitbucket_repository.test.owner
+  repo_slug      = bitbucket_repository.test.slug
+  filename       = "release.txt"
+  content        = "release"
+  branch         = "release"
+  commit_message = "Create release branch"
+  commit_author  = "Test <test@example.com>"
+}
+`, workspace, rName, mainbranch)
+}
+
+func testAccBitbucketRepoRenameCollisionConfig(workspace, rName, rOtherName string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  deletion_protection = false
+}
+
+resource "bitbucket_repository" "other" {
+  owner = %[1]q
+  name  = %[3]q
+
+  deletion_protection = false
+}
+`, workspace, rName, rOtherName)
+}
+
+func testAccBitbucketRepoMoveProjectConfig(workspace, rName, project string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_project" "first" {
+  owner = %[1]q
+  name  = "%[2]s-first"
+  key   = "TFFIRST"
+
+  deletion_protection = false
+}
+
+resource "bitbucket_project" "second" {
+  owner = %[1]q
+  name  = "%[2]s-second"
+  key   = "TFSECOND"
+
+  deletion_protection = false
+}
+
+resource "bitbucket_repository" "test" {
+  owner       = %[1]q
+  name        = %[2]q
+  project_key = bitbucket_project.%[3]s.key
+
+  deletion_protection = false
+}
+`, workspace, rName, project)
+}
+
+func testAccBitbucketRepoDeletionConfig(workspace, rName string, protection bool, onDestroy string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  deletion_protection = %[3]t
+  on_destroy          = %[4]q
+}
+`, workspace, rName, protection, onDestroy)
+}
+
+func testAccBitbucketRepoImportSourceConfig(workspace, rName, sourceURL string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  import_source {
+    url      = %[3]q
+    username = "user"
+    password = "secret"
+  }
+
+  deletion_protection = false
+}
+`, workspace, rName, sourceURL)
+}
+
+func testAccBitbucketRepoAvatarFileConfig(workspace, rName, avatarFile string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner       = %[1]q
+  name        = %[2]q
+  avatar_file = %[3]q
+
+  deletion_protection = false
+}
+`, workspace, rName, avatarFile)
+}
+
+func testAccBitbucketRepoWebsiteConfig(workspace, rName, website string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner   = %[1]q
+  name    = %[2]q
+  website = %[3]q
+
+  deletion_protection = false
+}
+`, workspace, rName, website)
+}
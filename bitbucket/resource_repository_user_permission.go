@@ -24,15 +24,16 @@ type RepositoryUser struct {
 	UUID string `json:"uuid,omitempty"`
 }
 
+var repositoryUserPermissionIdentity = newResourceIdentity(":", "workspace", "repo_slug", "user_id")
+
 func resourceRepositoryUserPermission() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceRepositoryUserPermissionPut,
 		ReadWithoutTimeout:   resourceRepositoryUserPermissionRead,
 		UpdateWithoutTimeout: resourceRepositoryUserPermissionPut,
 		DeleteWithoutTimeout: resourceRepositoryUserPermissionDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             repositoryUserPermissionIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             repositoryUserPermissionIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -153,6 +154,10 @@ func resourceRepositoryUserPermissionRead(ctx context.Context, d *schema.Resourc
 	d.Set("workspace", workspace)
 	d.Set("repo_slug", repoSlug)
 
+	if err := repositoryUserPermissionIdentity.set(d, workspace, repoSlug, userSlug); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -90,6 +90,8 @@ func testAccBitbucketRepositoryUserPermissionConfig(workspace, rName, permission
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 data "bitbucket_current_user" "test" {}
@@ -7,17 +7,32 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/strollby/bitbucket-go-client"
 )
 
+var repositoryVariableIdentity = newResourceIdentity("/", "workspace", "repo_slug", "uuid")
+
 func resourceRepositoryVariable() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceRepositoryVariableCreate,
 		UpdateWithoutTimeout: resourceRepositoryVariableUpdate,
 		ReadWithoutTimeout:   resourceRepositoryVariableRead,
 		DeleteWithoutTimeout: resourceRepositoryVariableDelete,
+		CustomizeDiff:        customizePipelineVariableDiff,
+		Importer: repositoryVariableIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			idParts := strings.Split(d.Id(), "/")
+			if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/VARIABLE-UUID", d.Id())
+			}
+			d.Set("repository", strings.Join(idParts[:2], "/"))
+			d.Set("uuid", idParts[2])
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: repositoryVariableIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"uuid": {
@@ -29,9 +44,38 @@ func resourceRepositoryVariable() *schema.Resource {
 				Required: true,
 			},
 			"value": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"value", "value_wo"},
+			},
+			"value_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				ExactlyOneOf: []string{"value", "value_wo"},
+				RequiredWith: []string{"value_wo_version"},
+			},
+			"value_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				RequiredWith: []string{"value_wo"},
+			},
+			// Kept in private state, see privateStateAttributes.
+			"value_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"value_in_sync": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"detect_secured_drift": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"rewrite", "warn"}, false),
 			},
 			"secured": {
 				Type:     schema.TypeBool,
@@ -49,7 +93,7 @@ func resourceRepositoryVariable() *schema.Resource {
 func newRepositoryVariableFromResource(d *schema.ResourceData) bitbucket.PipelineVariable {
 	dk := bitbucket.PipelineVariable{
 		Key:     d.Get("key").(string),
-		Value:   d.Get("value").(string),
+		Value:   pipelineVariableValue(d),
 		Secured: d.Get("secured").(bool),
 	}
 	return dk
@@ -74,6 +118,10 @@ func resourceRepositoryVariableCreate(ctx context.Context, d *schema.ResourceDat
 	d.Set("uuid", rvRes.Uuid)
 	d.SetId(rvRes.Key)
 
+	if err := setPipelineVariableValueHash(d, rvcr.Value); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceRepositoryVariableRead(ctx, d, m)
 }
 
@@ -99,17 +147,24 @@ func resourceRepositoryVariableRead(ctx context.Context, d *schema.ResourceData,
 		return diag.FromErr(err)
 	}
 
+	d.SetId(rvRes.Key)
 	d.Set("uuid", rvRes.Uuid)
 	d.Set("key", rvRes.Key)
 	d.Set("secured", rvRes.Secured)
 
-	if !rvRes.Secured {
+	if _, ok := d.GetOk("value_wo_version"); ok {
+		d.Set("value", "")
+	} else if !rvRes.Secured {
 		d.Set("value", rvRes.Value)
 	} else {
 		d.Set("value", d.Get("value").(string))
 	}
 
-	return nil
+	if err := repositoryVariableIdentity.set(d, workspace, repoSlug, rvRes.Uuid); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return checkPipelineVariableDrift(d, rvRes.Secured, rvRes.Value)
 }
 
 func resourceRepositoryVariableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -129,6 +184,10 @@ func resourceRepositoryVariableUpdate(ctx context.Context, d *schema.ResourceDat
 		return diag.FromErr(err)
 	}
 
+	if err := setPipelineVariableValueHash(d, rvcr.Value); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceRepositoryVariableRead(ctx, d, m)
 }
 
@@ -158,3 +217,102 @@ func repoVarId(repo string) (string, string, error) {
 		return "", "", fmt.Errorf("incorrect ID format, should match `owner/key`")
 	}
 }
+
+// pipelineVariableValue returns the value to send for a pipeline variable. The
+// write-only value_wo argument is only present in the raw configuration and is
+// preferred over value when set, so that it never has to be read from state.
+func pipelineVariableValue(d *schema.ResourceData) string {
+	if v, ok := pipelineVariableWriteOnlyValue(d.GetRawConfig()); ok {
+		return v
+	}
+
+	return d.Get("value").(string)
+}
+
+func pipelineVariableWriteOnlyValue(config cty.Value) (string, bool) {
+	if config.IsNull() || !config.IsKnown() {
+		return "", false
+	}
+
+	wo := config.GetAttr("value_wo")
+	if wo.IsNull() || !wo.IsKnown() {
+		return "", false
+	}
+
+	return wo.AsString(), true
+}
+
+// customizePipelineVariableDiff plans a rewrite of the variable value when the
+// last refresh found it out of sync, when the configured write-only value no
+// longer matches the hash of the last written value, or on every apply of a
+// secured variable in "rewrite" drift mode. The hash is kept in private state,
+// so only value_in_sync shows in the plan.
+func customizePipelineVariableDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	if d.Get("secured").(bool) && d.Get("detect_secured_drift").(string) == "rewrite" {
+		return d.SetNewComputed("value_in_sync")
+	}
+
+	// States written before value_in_sync existed have no value for it.
+	if state := d.GetRawState(); !state.IsNull() {
+		if inSync := state.GetAttr("value_in_sync"); !inSync.IsNull() && inSync.False() {
+			return d.SetNewComputed("value_in_sync")
+		}
+	}
+
+	if v, ok := pipelineVariableWriteOnlyValue(d.GetRawConfig()); ok {
+		if !secretMatchesHash(v, d.Get("value_hash").(string)) {
+			log.Printf("[DEBUG] Pipeline Variable (%s) write-only value changed, planning rewrite", d.Id())
+			return d.SetNewComputed("value_in_sync")
+		}
+	}
+
+	return nil
+}
+
+// setPipelineVariableValueHash records a salted hash of the value last written to Bitbucket.
+func setPipelineVariableValueHash(d *schema.ResourceData, value string) error {
+	hash, err := hashSecret(value)
+	if err != nil {
+		return err
+	}
+
+	return d.Set("value_hash", hash)
+}
+
+// checkPipelineVariableDrift compares the remote value of a pipeline variable
+// with the hash of the last written value, and records whether they match in
+// value_in_sync. Bitbucket never returns secured values, so for those it can
+// only warn when asked to.
+func checkPipelineVariableDrift(d *schema.ResourceData, secured bool, remoteValue string) diag.Diagnostics {
+	d.Set("value_in_sync", true)
+
+	hash := d.Get("value_hash").(string)
+	if hash == "" {
+		return nil
+	}
+
+	if !secured {
+		if !secretMatchesHash(remoteValue, hash) {
+			log.Printf("[WARN] Pipeline Variable (%s) value changed outside of Terraform", d.Id())
+			d.Set("value_in_sync", false)
+		}
+		return nil
+	}
+
+	if d.Get("detect_secured_drift").(string) == "warn" {
+		return diag.Diagnostics{
+			{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Secured value of pipeline variable %q cannot be verified", d.Get("key").(string)),
+				Detail: "Bitbucket does not return the values of secured variables, so changes made outside of Terraform " +
+					"cannot be detected. Set detect_secured_drift to \"rewrite\" to write the value on every apply.",
+			},
+		}
+	}
+
+	return nil
+}
@@ -11,6 +11,38 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
+func TestBitbucketRepositoryVariable_SecretHash(t *testing.T) {
+	t.Parallel()
+
+	hash, err := hashSecret("s3cr3t")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if !secretMatchesHash("s3cr3t", hash) {
+		t.Errorf("expected %q to match its own hash %q", "s3cr3t", hash)
+	}
+
+	if secretMatchesHash("other", hash) {
+		t.Errorf("expected %q not to match hash %q", "other", hash)
+	}
+
+	other, err := hashSecret("s3cr3t")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if other == hash {
+		t.Errorf("expected hashes of the same value to be salted differently, got %q twice", hash)
+	}
+
+	for _, encoded := range []string{"", "s3cr3t", "sha256$abc$def", "argon2id$!!$def"} {
+		if secretMatchesHash("s3cr3t", encoded) {
+			t.Errorf("expected malformed hash %q not to match", encoded)
+		}
+	}
+}
+
 func TestAccBitbucketRepositoryVariable_basic(t *testing.T) {
 
 	owner := os.Getenv("BITBUCKET_TEAM")
@@ -46,6 +78,44 @@ func TestAccBitbucketRepositoryVariable_basic(t *testing.T) {
 	})
 }
 
+func TestAccBitbucketRepositoryVariable_writeOnly(t *testing.T) {
+
+	owner := os.Getenv("BITBUCKET_TEAM")
+	rName := acctest.RandomWithPrefix("tf-test")
+	resourceName := "bitbucket_repository_variable.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
+		CheckDestroy:             testAccCheckBitbucketRepositoryVariableDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketRepositoryVariableWriteOnlyConfig(owner, rName, "test-val", 1),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "key", "test"),
+					resource.TestCheckResourceAttr(resourceName, "value", ""),
+					resource.TestCheckNoResourceAttr(resourceName, "value_wo"),
+					resource.TestCheckResourceAttr(resourceName, "value_wo_version", "1"),
+					resource.TestCheckResourceAttr(resourceName, "secured", "true"),
+					resource.TestCheckResourceAttr(resourceName, "value_in_sync", "true"),
+					resource.TestCheckNoResourceAttr(resourceName, "value_hash"),
+				),
+			},
+			{
+				Config: testAccBitbucketRepositoryVariableWriteOnlyConfig(owner, rName, "test-val-2", 2),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketRepositoryVariableExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "value", ""),
+					resource.TestCheckNoResourceAttr(resourceName, "value_wo"),
+					resource.TestCheckResourceAttr(resourceName, "value_wo_version", "2"),
+					resource.TestCheckResourceAttr(resourceName, "value_in_sync", "true"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckBitbucketRepositoryVariableDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(Clients).genClient
 	pipeApi := client.ApiClient.PipelinesApi
@@ -90,6 +160,8 @@ func testAccBitbucketRepositoryVariableConfig(team, rName, val string) string {
 resource "bitbucket_repository" "test" {
   owner = %[1]q
   name  = %[2]q
+
+  deletion_protection = false
 }
 
 resource "bitbucket_repository_variable" "test" {
@@ -99,3 +171,22 @@ resource "bitbucket_repository_variable" "test" {
 }
 `, team, rName, val)
 }
+
+func testAccBitbucketRepositoryVariableWriteOnlyConfig(team, rName, val string, version int) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner = %[1]q
+  name  = %[2]q
+
+  deletion_protection = false
+}
+
+resource "bitbucket_repository_variable" "test" {
+  key              = "test"
+  value_wo         = %[3]q
+  value_wo_version = %[4]d
+  secured          = true
+  repository       = bitbucket_repository.test.id
+}
+`, team, rName, val, version)
+}
@@ -22,15 +22,16 @@ type SshKey struct {
 	Comment string `json:"comment,omitempty"`
 }
 
+var sshKeyIdentity = newResourceIdentity("/", "user", "uuid")
+
 func resourceSshKey() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceSshKeysCreate,
 		ReadWithoutTimeout:   resourceSshKeysRead,
 		UpdateWithoutTimeout: resourceSshKeysUpdate,
 		DeleteWithoutTimeout: resourceSshKeysDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		Importer:             sshKeyIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             sshKeyIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"user": {
@@ -111,6 +112,10 @@ func resourceSshKeysRead(ctx context.Context, d *schema.ResourceData, m interfac
 	d.Set("uuid", sshKeyReq.Uuid)
 	d.Set("comment", sshKeyReq.Comment)
 
+	if err := sshKeyIdentity.set(d, user, keyId); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,263 @@
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strollby/bitbucket-go-client"
+)
+
+var tagIdentity = newResourceIdentity("/", "workspace", "repo_slug", "name")
+
+func resourceTag() *schema.Resource {
+	return &schema.Resource{
+		CreateWithoutTimeout: resourceTagCreate,
+		ReadWithoutTimeout:   resourceTagRead,
+		DeleteWithoutTimeout: resourceTagDelete,
+		Importer: tagIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			// Tag names may contain slashes, so prefer the identity when importing by identity.
+			idParts, err := tagIdentity.values(d)
+			if err != nil {
+				idParts = strings.SplitN(d.Id(), "/", 3)
+			}
+			if len(idParts) != 3 || idParts[0] == "" || idParts[1] == "" || idParts[2] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/TAG", d.Id())
+			}
+			d.SetId(strings.Join(idParts, "/"))
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: tagIdentity.identitySchema(),
+
+		Schema: map[string]*schema.Schema{
+			"workspace": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"repo_slug": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.All(
+					validation.StringIsNotWhiteSpace,
+					validation.StringDoesNotMatch(regexp.MustCompile(`^refs/`), "must be a tag name, not a ref"),
+				),
+			},
+			"target": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				// The target of an imported tag is unknown.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Id() != "" && old == ""
+				},
+			},
+			"message": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				// Imported lightweight tags have no message.
+				DiffSuppressFunc: func(k, old, new string, d *schema.ResourceData) bool {
+					return d.Id() != "" && old == ""
+				},
+			},
+			"target_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"tagger": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+type tagBody struct {
+	Name    string           `json:"name"`
+	Target  branchBodyTarget `json:"target"`
+	Message string           `json:"message,omitempty"`
+}
+
+// newTagFromResource returns the request body creating the tag of d at hash,
+// the commit its target resolves to.
+func newTagFromResource(d *schema.ResourceData, hash string) *tagBody {
+	return &tagBody{
+		Name:    d.Get("name").(string),
+		Target:  branchBodyTarget{Hash: hash},
+		Message: d.Get("message").(string),
+	}
+}
+
+func resourceTagCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace := d.Get("workspace").(string)
+	repoSlug := d.Get("repo_slug").(string)
+	name := d.Get("name").(string)
+	target := d.Get("target").(string)
+	id := fmt.Sprintf("%s/%s/%s", workspace, repoSlug, name)
+
+	existing, err := getTag(&client, workspace, repoSlug, name)
+	if err != nil {
+		return diag.Errorf("error reading Tag (%s): %s", id, err)
+	}
+	if existing != nil {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Tag (%s) already exists", id),
+			Detail:   fmt.Sprintf("To manage the existing tag, import it with: terraform import <address> %s", id),
+		}}
+	}
+
+	hash, err := resolveCommit(m.(Clients), workspace, repoSlug, target)
+	if err != nil {
+		return diag.Errorf("error resolving target %q of Tag (%s): %s", target, id, err)
+	}
+
+	payload, err := json.Marshal(newTagFromResource(d, hash))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Tag Request: %s", string(payload))
+	_, err = client.Post(fmt.Sprintf("2.0/repositories/%s/%s/refs/tags", url.PathEscape(workspace), url.PathEscape(repoSlug)), bytes.NewBuffer(payload))
+	if err != nil {
+		return diag.Errorf("error creating Tag (%s): %s", id, err)
+	}
+
+	d.SetId(id)
+
+	return resourceTagRead(ctx, d, m)
+}
+
+func resourceTagRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, name, err := tagId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	tag, err := getTag(&client, workspace, repoSlug, name)
+	if err != nil {
+		return diag.Errorf("error reading Tag (%s): %s", d.Id(), err)
+	}
+	if tag == nil {
+		log.Printf("[WARN] Tag (%s) not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("workspace", workspace)
+	d.Set("repo_slug", repoSlug)
+	for k, v := range flattenTag(*tag) {
+		d.Set(k, v)
+	}
+
+	if err := tagIdentity.set(d, workspace, repoSlug, name); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func resourceTagDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(Clients).httpClient
+
+	workspace, repoSlug, name, err := tagId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	res, err := client.Delete(tagEndpoint(workspace, repoSlug, name))
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("error deleting Tag (%s): %s", d.Id(), err)
+	}
+
+	return nil
+}
+
+// tagEndpoint returns the endpoint of a tag, whose name may contain slashes
+// and other characters that must be escaped in the path.
+func tagEndpoint(workspace, repoSlug, name string) string {
+	return fmt.Sprintf("2.0/repositories/%s/%s/refs/tags/%s",
+		url.PathEscape(workspace), url.PathEscape(repoSlug), url.PathEscape(name))
+}
+
+// getTag returns a tag, or nil when it does not exist.
+func getTag(client *Client, workspace, repoSlug, name string) (*bitbucket.Tag, error) {
+	res, err := client.Get(tagEndpoint(workspace, repoSlug, name))
+	if res != nil && res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var tag bitbucket.Tag
+	if err := json.NewDecoder(res.Body).Decode(&tag); err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+// flattenTag returns the attributes of a tag shared by the bitbucket_tag
+// resource and the bitbucket_tags data source.
+func flattenTag(tag bitbucket.Tag) map[string]interface{} {
+	flat := map[string]interface{}{
+		"name":    tag.Name,
+		"message": strings.TrimSuffix(tag.Message, "\n"),
+	}
+
+	if tag.Target != nil {
+		flat["target_hash"] = tag.Target.Hash
+	}
+
+	if tag.Tagger != nil {
+		flat["tagger"] = tag.Tagger.Raw
+	}
+
+	if !tag.Date.IsZero() {
+		flat["date"] = tag.Date.Format(time.RFC3339)
+	}
+
+	return flat
+}
+
+func tagId(id string) (string, string, string, error) {
+	parts := strings.SplitN(id, "/", 3)
+
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", fmt.Errorf("unexpected format of ID (%q), expected WORKSPACE/REPO-SLUG/TAG", id)
+	}
+
+	return parts[0], parts[1], parts[2], nil
+}
@@ -0,0 +1,223 @@
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccBitbucketTag_basic(t *testing.T) {
+	rName := acctest.RandomWithPrefix("tf-test")
+	workspace := os.Getenv("BITBUCKET_TEAM")
+	resourceName := "bitbucket_tag.test"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckBitbucketTagDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccBitbucketTagConfig(workspace, rName, "main"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketTagExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "name", "v1.0.0"),
+					resource.TestCheckResourceAttr(resourceName, "target", "main"),
+					resource.TestCheckResourceAttr(resourceName, "message", "Release 1.0.0"),
+					resource.TestCheckResourceAttrSet(resourceName, "target_hash"),
+					resource.TestCheckResourceAttrSet(resourceName, "tagger"),
+					resource.TestCheckResourceAttrSet(resourceName, "date"),
+				),
+			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"target"},
+			},
+			{
+				Config: testAccBitbucketTagConfig(workspace, rName, "release/1.0"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckBitbucketTagExists(resourceName),
+					resource.TestCheckResourceAttr(resourceName, "target", "release/1.0"),
+					resource.TestCheckResourceAttrPair(resourceName, "target_hash", "bitbucket_commit_file.test", "commit_sha"),
+				),
+			},
+		},
+	})
+}
+
+func TestResourceTagMessageDiff(t *testing.T) {
+	cases := map[string]struct {
+		stateMessage string
+		config       map[string]interface{}
+		forcesNew    bool
+	}{
+		"imported lightweight tag with a message": {
+			stateMessage: "",
+			config:       map[string]interface{}{"message": "Release 1.0.0"},
+		},
+		"imported lightweight tag without a message": {
+			stateMessage: "",
+			config:       map[string]interface{}{},
+		},
+		"annotated tag without a message": {
+			stateMessage: "Release 1.0.0",
+			config:       map[string]interface{}{},
+		},
+		"changed message": {
+			stateMessage: "Release 1.0.0",
+			config:       map[string]interface{}{"message": "Release 1.0.1"},
+			forcesNew:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		config := map[string]interface{}{
+			"workspace": "workspace",
+			"repo_slug": "repo",
+			"name":      "v1.0.0",
+			"target":    "main",
+		}
+		for k, v := range tc.config {
+			config[k] = v
+		}
+
+		state := &terraform.InstanceState{
+			ID: "workspace/repo/v1.0.0",
+			Attributes: map[string]string{
+				"id":          "workspace/repo/v1.0.0",
+				"workspace":   "workspace",
+				"repo_slug":   "repo",
+				"name":        "v1.0.0",
+				"target":      "main",
+				"message":     tc.stateMessage,
+				"target_hash": "abc123",
+			},
+		}
+
+		diff, err := resourceTag().Diff(context.Background(), state, terraform.NewResourceConfigRaw(config), nil)
+		if err != nil {
+			t.Fatalf("%s: err: %s", name, err)
+		}
+
+		if forcesNew := diff != nil && diff.RequiresNew(); forcesNew != tc.forcesNew {
+			t.Errorf("%s: expected the diff to force a new tag to be %t, got %t", name, tc.forcesNew, forcesNew)
+		}
+	}
+}
+
+func TestResourceTag_escapedName(t *testing.T) {
+	clients, err := newClients(providerSettings{
+		Username: "user",
+		Password: "password",
+		HTTPClient: &http.Client{Transport: listResourceTestTransport{
+			"/2.0/repositories/ws/repo/refs/tags/release%2F1.0%23x": `{"name": "release/1.0#x", "target": {"hash": "4f3b3c2a1d0e"}}`,
+		}},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := resourceTag().Data(&terraform.InstanceState{ID: "ws/repo/release/1.0#x"})
+
+	if diags := resourceTagRead(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error reading the tag: %v", diags)
+	}
+	if d.Id() == "" {
+		t.Fatalf("expected the tag to be found")
+	}
+	if name := d.Get("name").(string); name != "release/1.0#x" {
+		t.Errorf("expected name %q, got %q", "release/1.0#x", name)
+	}
+	if hash := d.Get("target_hash").(string); hash != "4f3b3c2a1d0e" {
+		t.Errorf("expected target_hash %q, got %q", "4f3b3c2a1d0e", hash)
+	}
+
+	if diags := resourceTagDelete(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error deleting the tag: %v", diags)
+	}
+}
+
+func testAccCheckBitbucketTagDestroy(s *terraform.State) error {
+	client := testAccProvider.Meta().(Clients).httpClient
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "bitbucket_tag" {
+			continue
+		}
+
+		workspace, repoSlug, name, err := tagId(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		tag, err := getTag(&client, workspace, repoSlug, name)
+		if err != nil {
+			return fmt.Errorf("unexpected error checking Tag (%s): %s", rs.Primary.ID, err)
+		}
+		if tag != nil {
+			return fmt.Errorf("Tag (%s) still exists", rs.Primary.ID)
+		}
+	}
+
+	return nil
+}
+
+func testAccCheckBitbucketTagExists(n string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Not found %s", n)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No Tag ID is set")
+		}
+		return nil
+	}
+}
+
+func testAccBitbucketTagConfig(workspace, rName, target string) string {
+	return fmt.Sprintf(`
+resource "bitbucket_repository" "test" {
+  owner      = %[1]q
+  name       = %[2]q
+  initialize = true
+
+  deletion_protection = false
+}
+
+resource "bitbucket_branch" "test" {
+  workspace    = bitbucket_repository.test.owner
+  repo_slug    = bitbucket_repository.test.slug
+  name         = "release/1.0"
+  source       = "main"
+  force_delete = true
+}
+
+resource "bitbucket_commit_file" "test" {
+  workspace      = bitbucket_branch.test.workspace
+  repo_slug      = bitbucket_branch.test.repo_slug
+  branch         = bitbucket_branch.test.name
+  filename       = "VERSION"
+  content        = "1.0.0"
+  commit_author  = "Unit test <unit@test.local>"
+  commit_message = "Release 1.0.0"
+}
+
+resource "bitbucket_tag" "test" {
+  workspace = bitbucket_repository.test.owner
+  repo_slug = bitbucket_repository.test.slug
+  name      = "v1.0.0"
+  target    = %[3]q
+  message   = "Release 1.0.0"
+
+  depends_on = [bitbucket_commit_file.test]
+}
+`, workspace, rName, target)
+}
@@ -16,23 +16,24 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
+var workspaceHookIdentity = newResourceIdentity("/", "workspace", "uuid")
+
 func resourceWorkspaceHook() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceWorkspaceHookCreate,
 		ReadWithoutTimeout:   resourceWorkspaceHookRead,
 		UpdateWithoutTimeout: resourceWorkspaceHookUpdate,
 		DeleteWithoutTimeout: resourceWorkspaceHookDelete,
-		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				idParts := strings.Split(d.Id(), "/")
-				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected workspace/REPO/HOOK-ID", d.Id())
-				}
-				d.SetId(idParts[1])
-				d.Set("workspace", idParts[0])
-				return []*schema.ResourceData{d}, nil
-			},
-		},
+		Importer: workspaceHookIdentity.importer(func(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			idParts := strings.Split(d.Id(), "/")
+			if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+				return nil, fmt.Errorf("unexpected format of ID (%q), expected workspace/REPO/HOOK-ID", d.Id())
+			}
+			d.SetId(idParts[1])
+			d.Set("workspace", idParts[0])
+			return []*schema.ResourceData{d}, nil
+		}),
+		Identity: workspaceHookIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"workspace": {
@@ -172,6 +173,10 @@ func resourceWorkspaceHookRead(ctx context.Context, d *schema.ResourceData, m in
 		d.Set("events", hook.Events)
 	}
 
+	if err := workspaceHookIdentity.set(d, d.Get("workspace").(string), d.Id()); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return nil
 }
 
@@ -7,21 +7,24 @@ import (
 	"net/http"
 	"strings"
 
-	"github.com/strollby/bitbucket-go-client"
 	"github.com/antihax/optional"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/strollby/bitbucket-go-client"
 )
 
+var workspaceVariableIdentity = newResourceIdentity("/", "workspace", "uuid")
+
 func resourceWorkspaceVariable() *schema.Resource {
 	return &schema.Resource{
 		CreateWithoutTimeout: resourceWorkspaceVariableCreate,
 		UpdateWithoutTimeout: resourceWorkspaceVariableUpdate,
 		ReadWithoutTimeout:   resourceWorkspaceVariableRead,
 		DeleteWithoutTimeout: resourceWorkspaceVariableDelete,
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
-		},
+		CustomizeDiff:        customizePipelineVariableDiff,
+		Importer:             workspaceVariableIdentity.importer(schema.ImportStatePassthroughContext),
+		Identity:             workspaceVariableIdentity.identitySchema(),
 
 		Schema: map[string]*schema.Schema{
 			"uuid": {
@@ -33,9 +36,38 @@ func resourceWorkspaceVariable() *schema.Resource {
 				Required: true,
 			},
 			"value": {
-				Type:      schema.TypeString,
-				Required:  true,
-				Sensitive: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				ExactlyOneOf: []string{"value", "value_wo"},
+			},
+			"value_wo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Sensitive:    true,
+				WriteOnly:    true,
+				ExactlyOneOf: []string{"value", "value_wo"},
+				RequiredWith: []string{"value_wo_version"},
+			},
+			"value_wo_version": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				RequiredWith: []string{"value_wo"},
+			},
+			// Kept in private state, see privateStateAttributes.
+			"value_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"value_in_sync": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"detect_secured_drift": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"rewrite", "warn"}, false),
 			},
 			"secured": {
 				Type:     schema.TypeBool,
@@ -53,7 +85,7 @@ func resourceWorkspaceVariable() *schema.Resource {
 func newWorkspaceVariableFromResource(d *schema.ResourceData) bitbucket.PipelineVariable {
 	dk := bitbucket.PipelineVariable{
 		Key:     d.Get("key").(string),
-		Value:   d.Get("value").(string),
+		Value:   pipelineVariableValue(d),
 		Secured: d.Get("secured").(bool),
 	}
 	return dk
@@ -82,6 +114,10 @@ func resourceWorkspaceVariableCreate(ctx context.Context, d *schema.ResourceData
 
 	d.SetId(fmt.Sprintf("%s/%s", workspace, rvRes.Uuid))
 
+	if err := setPipelineVariableValueHash(d, rvcr.Value); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceWorkspaceVariableRead(ctx, d, m)
 }
 
@@ -113,13 +149,19 @@ func resourceWorkspaceVariableRead(ctx context.Context, d *schema.ResourceData,
 	d.Set("key", rvRes.Key)
 	d.Set("secured", rvRes.Secured)
 
-	if !rvRes.Secured {
+	if _, ok := d.GetOk("value_wo_version"); ok {
+		d.Set("value", "")
+	} else if !rvRes.Secured {
 		d.Set("value", rvRes.Value)
 	} else {
 		d.Set("value", d.Get("value").(string))
 	}
 
-	return nil
+	if err := workspaceVariableIdentity.set(d, workspace, uuid); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return checkPipelineVariableDrift(d, rvRes.Secured, rvRes.Value)
 }
 
 func resourceWorkspaceVariableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -138,6 +180,10 @@ func resourceWorkspaceVariableUpdate(ctx context.Context, d *schema.ResourceData
 		return diag.FromErr(err)
 	}
 
+	if err := setPipelineVariableValueHash(d, rvcr.Value); err != nil {
+		return diag.FromErr(err)
+	}
+
 	return resourceWorkspaceVariableRead(ctx, d, m)
 }
 
@@ -0,0 +1,883 @@
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// schemaAudit describes how a resource talks to the API, to check that every
+// attribute of its schema is either sent to the API or read from it.
+type schemaAudit struct {
+	resource *schema.Resource
+	// config is a minimal configuration the attributes are varied from.
+	config map[string]interface{}
+	// samples holds two differing values of attributes that are not strings,
+	// bools or ints.
+	samples map[string][2]interface{}
+	// body builds the request body sent to the API.
+	body func(d *schema.ResourceData) (interface{}, error)
+	// id and api are the ID read and the API responses it is read from. The
+	// responses should have every field set to a non-zero value.
+	id  string
+	api listResourceTestTransport
+	// state holds the attributes Read needs besides the ID, such as the
+	// parts of the endpoint.
+	state map[string]string
+	// ignored lists the attributes that are by design neither sent nor read,
+	// with the reason.
+	ignored map[string]string
+}
+
+var testRepositoryAPI = listResourceTestTransport{
+	"/2.0/repositories/ws/repo": `{
+		"scm": "git",
+		"name": "Repo",
+		"slug": "repo",
+		"full_name": "ws/repo",
+		"uuid": "{repo}",
+		"is_private": true,
+		"has_wiki": true,
+		"has_issues": true,
+		"language": "go",
+		"fork_policy": "no_forks",
+		"website": "https://example.com",
+		"description": "description",
+		"project": {"key": "PROJ"},
+		"mainbranch": {"type": "branch", "name": "main"},
+		"parent": {"full_name": "other/parent", "uuid": "{parent}"},
+		"links": {
+			"clone": [{"name": "https", "href": "https://bitbucket.org/ws/repo.git"}, {"name": "ssh", "href": "git@bitbucket.org:ws/repo.git"}],
+			"avatar": {"href": "https://bytebucket.org/ravatar/repo"}
+		}
+	}`,
+	"/2.0/repositories/ws/repo/pipelines_config":   `{"enabled": true}`,
+	"/2.0/repositories/ws/repo/override-settings":  `{"default_merge_strategy": true, "branching_model": true}`,
+	"/2.0/repositories/ws/repo/refs/branches/main": `{"name": "main", "target": {"hash": "4f3b3c2a1d0e"}}`,
+}
+
+var testLinkSamples = [2]interface{}{
+	[]interface{}{map[string]interface{}{"avatar": []interface{}{map[string]interface{}{"href": "https://example.com/a.png"}}}},
+	[]interface{}{map[string]interface{}{"avatar": []interface{}{map[string]interface{}{"href": "https://example.com/b.png"}}}},
+}
+
+var testBranchingModelSamples = map[string][2]interface{}{
+	"branch_type": {
+		[]interface{}{map[string]interface{}{"kind": "feature", "prefix": "feature/"}},
+		[]interface{}{map[string]interface{}{"kind": "bugfix", "prefix": "bugfix/"}},
+	},
+	"development": {
+		[]interface{}{map[string]interface{}{"name": "develop"}},
+		[]interface{}{map[string]interface{}{"use_mainbranch": true}},
+	},
+	"production": {
+		[]interface{}{map[string]interface{}{"name": "main"}},
+		[]interface{}{map[string]interface{}{"use_mainbranch": true}},
+	},
+}
+
+const testBranchingModelAPI = `{
+	"development": {"name": "develop", "is_valid": true, "use_mainbranch": true, "branch_does_not_exist": true},
+	"production": {"name": "main", "is_valid": true, "use_mainbranch": true, "branch_does_not_exist": true},
+	"branch_types": [{"kind": "feature", "prefix": "feature/", "enabled": true}]
+}`
+
+var testPipelineScheduleTargetSamples = [2]interface{}{
+	[]interface{}{map[string]interface{}{
+		"ref_name": "main",
+		"ref_type": "branch",
+		"selector": []interface{}{map[string]interface{}{"pattern": "main"}},
+	}},
+	[]interface{}{map[string]interface{}{
+		"ref_name": "v1.0.0",
+		"ref_type": "tag",
+		"selector": []interface{}{map[string]interface{}{"type": "tags", "pattern": "v*"}},
+	}},
+}
+
+var testKnownHostKeySamples = [2]interface{}{
+	[]interface{}{map[string]interface{}{"key_type": "ssh-ed25519", "key": "AAAA"}},
+	[]interface{}{map[string]interface{}{"key_type": "ssh-rsa", "key": "BBBB"}},
+}
+
+// testPipelineVariableIgnored returns the attributes of pipeline variables
+// that are neither sent nor read, along with the attributes naming their
+// endpoint.
+func testPipelineVariableIgnored(endpoint ...string) map[string]string {
+	ignored := map[string]string{
+		"value_wo":             "write-only, sent from the raw configuration, see pipelineVariableValue",
+		"value_wo_version":     "only plans a rewrite of value_wo",
+		"value_hash":           "hash of the last written value, kept in private state",
+		"detect_secured_drift": "only changes how drift of secured values is reported",
+	}
+	for _, attribute := range endpoint {
+		ignored[attribute] = "names the endpoint"
+	}
+
+	return ignored
+}
+
+// schemaAuditExemptions lists the resources TestSchemaAudit does not audit,
+// with the reason. Only resources without a request body are exempted.
+var schemaAuditExemptions = map[string]string{
+	"bitbucket_default_reviewers":         "each reviewer is added with a request to its own endpoint, without a body",
+	"bitbucket_group_membership":          "the group and the member name the endpoint, there is no request body",
+	"bitbucket_project_default_reviewers": "each reviewer is added with a request to its own endpoint, without a body",
+}
+
+// testSchemaAuditFiles writes files into a new directory and returns its path.
+func testSchemaAuditFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	return dir
+}
+
+// srcCommitAuditBody returns the fields of a commit, which is sent as form data
+// rather than a JSON body.
+func srcCommitAuditBody(commit srcCommit, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"message":     commit.message,
+		"author":      commit.author,
+		"branch":      commit.branch,
+		"files":       commit.files,
+		"deleted":     commit.deleted,
+		"executables": commit.executables,
+		"symlinks":    commit.symlinks,
+		"parents":     commit.parents,
+	}, nil
+}
+
+func TestSchemaAudit(t *testing.T) {
+	sourceDirs := [2]string{
+		testSchemaAuditFiles(t, map[string]string{"a.txt": "a", "b.md": "b"}),
+		testSchemaAuditFiles(t, map[string]string{"a.txt": "changed", "b.md": "b"}),
+	}
+	sourceFiles := [2]interface{}{
+		filepath.Join(sourceDirs[0], "a.txt"),
+		filepath.Join(sourceDirs[1], "a.txt"),
+	}
+
+	audits := map[string]schemaAudit{
+		"bitbucket_repository": {
+			config: map[string]interface{}{
+				"owner": "ws",
+				"name":  "repo",
+			},
+			samples: map[string][2]interface{}{
+				"link": testLinkSamples,
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return newRepositoryFromResource(d)
+			},
+			id:  "ws/repo",
+			api: testRepositoryAPI,
+			ignored: map[string]string{
+				"deletion_protection": "only guards deletion",
+				"on_destroy":          "only changes deletion",
+				"initialize":          "only makes a commit on creation",
+				"import_source":       "only pushes the source on creation",
+				"avatar_file":         "uploaded when avatar_sha256 changes",
+				"avatar_base64":       "uploaded when avatar_sha256 changes",
+				"avatar_sha256":       "hash of the last uploaded avatar",
+				"transfer_pending":    "tracks a transfer requested by the provider",
+			},
+		},
+		"bitbucket_forked_repository": {
+			config: map[string]interface{}{
+				"owner":  "ws",
+				"name":   "repo",
+				"parent": []interface{}{map[string]interface{}{"workspace": "other", "slug": "parent"}},
+			},
+			samples: map[string][2]interface{}{
+				"link": testLinkSamples,
+				"parent": {
+					[]interface{}{map[string]interface{}{"workspace": "other", "slug": "parent"}},
+					[]interface{}{map[string]interface{}{"workspace": "other", "slug": "other-parent"}},
+				},
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				repo, err := newRepositoryFromResource(d)
+				if err != nil {
+					return nil, err
+				}
+				return createForkedRepositoryFromRepository(repo, d.Get("owner").(string)), nil
+			},
+			id:  "ws/repo",
+			api: testRepositoryAPI,
+			ignored: map[string]string{
+				"deletion_protection":    "only guards deletion",
+				"on_destroy":             "only changes deletion",
+				"avatar_file":            "uploaded when avatar_sha256 changes",
+				"avatar_base64":          "uploaded when avatar_sha256 changes",
+				"avatar_sha256":          "hash of the last uploaded avatar",
+				"transfer_pending":       "tracks a transfer requested by the provider",
+				"sync_with_parent":       "only pushes the main branch of the parent",
+				"parent_mainbranch_hash": "only read with sync_with_parent",
+			},
+		},
+		"bitbucket_branch": {
+			config: map[string]interface{}{
+				"workspace": "ws",
+				"repo_slug": "repo",
+				"name":      "feature",
+				"source":    "main",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				// The source stands in for the commit it resolves to.
+				return newBranchFromResource(d, d.Get("source").(string)), nil
+			},
+			id: "ws/repo/feature",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/refs/branches/feature": `{"name": "feature", "target": {"hash": "4f3b3c2a1d0e"}}`,
+			},
+			ignored: map[string]string{
+				"force_delete": "only used on destroy",
+			},
+		},
+		"bitbucket_branching_model": {
+			config: map[string]interface{}{
+				"owner":       "ws",
+				"repository":  "repo",
+				"development": []interface{}{map[string]interface{}{"name": "develop"}},
+			},
+			samples: testBranchingModelSamples,
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandBranchingModel(d), nil
+			},
+			id: "ws/repo",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/branching-model": testBranchingModelAPI,
+			},
+		},
+		"bitbucket_branch_restriction": {
+			config: map[string]interface{}{
+				"owner":      "ws",
+				"repository": "repo",
+				"kind":       "push",
+				"pattern":    "main",
+			},
+			samples: map[string][2]interface{}{
+				"users": {
+					[]interface{}{"{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}"},
+					[]interface{}{"{7d2e4b1a-3f5c-4e8d-a9b0-1c2d3e4f5a6b}"},
+				},
+				"groups": {
+					[]interface{}{map[string]interface{}{"owner": "ws", "slug": "developers"}},
+					[]interface{}{map[string]interface{}{"owner": "ws", "slug": "admins"}},
+				},
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandBranchRestriction(d), nil
+			},
+			id: "1",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/branch-restrictions/1": `{
+					"id": 1,
+					"kind": "push",
+					"branch_match_kind": "glob",
+					"branch_type": "feature",
+					"pattern": "main",
+					"value": 2,
+					"users": [{"uuid": "{0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f}", "account_id": "557058:0f3a9e3c-5c71-4a6e-9d6b-4a3c2b1e0d9f"}],
+					"groups": [{"slug": "developers", "full_slug": "ws:developers", "workspace": {"slug": "ws"}}]
+				}`,
+			},
+			state: map[string]string{
+				"owner":      "ws",
+				"repository": "repo",
+			},
+			ignored: map[string]string{
+				"owner":      "names the endpoint",
+				"repository": "names the endpoint",
+			},
+		},
+		"bitbucket_commit_file": {
+			config: map[string]interface{}{
+				"workspace":      "ws",
+				"repo_slug":      "repo",
+				"branch":         "main",
+				"filename":       "a.txt",
+				"commit_message": "message",
+				"content":        "a",
+			},
+			samples: map[string][2]interface{}{
+				"source":         sourceFiles,
+				"content_base64": {"YQ==", "Yg=="},
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return srcCommitAuditBody(newCommitFileCommit(d))
+			},
+			id: "ws/repo/main/a.txt",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/refs/branches/main":                 `{"name": "main", "target": {"hash": "4f3b3c2a1d0e"}}`,
+				"/2.0/repositories/ws/repo/src/4f3b3c2a1d0e/a.txt?format=meta": `{"path": "a.txt", "type": "commit_file", "size": 7, "attributes": ["executable", "link"], "commit": {"hash": "1a2b3c4d5e6f"}}`,
+				"/2.0/repositories/ws/repo/src/4f3b3c2a1d0e/a.txt":             "changed",
+			},
+			state: map[string]string{
+				"workspace": "ws",
+				"repo_slug": "repo",
+				"branch":    "main",
+				"filename":  "a.txt",
+			},
+			ignored: map[string]string{
+				"workspace":              "names the endpoint",
+				"repo_slug":              "names the endpoint",
+				"delete_on_destroy":      "only used on destroy",
+				"normalize_line_endings": "only changes how the content on the branch is compared",
+				"expected_parent":        "sent as the parent of the commit, chosen when applying",
+				"strict_parent":          "sent as the parent of the commit, chosen when applying",
+				"rebase_on_conflict":     "only retries a commit rejected for its parent",
+				"commit_sha":             "set from the response of the commit",
+			},
+		},
+		"bitbucket_commit_files": {
+			config: map[string]interface{}{
+				"workspace":      "ws",
+				"repo_slug":      "repo",
+				"branch":         "main",
+				"commit_message": "message",
+				"source_dir":     sourceDirs[0],
+			},
+			samples: map[string][2]interface{}{
+				"files":       {map[string]interface{}{"a.txt": "a"}, map[string]interface{}{"a.txt": "b"}},
+				"source_dir":  {sourceDirs[0], sourceDirs[1]},
+				"source_glob": {"*.txt", "*.md"},
+				"executables": {[]interface{}{"a.txt"}, []interface{}{"b.md"}},
+				"symlinks":    {[]interface{}{"a.txt"}, []interface{}{"b.md"}},
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				files, err := commitFilesContent(d)
+				if err != nil {
+					return nil, err
+				}
+				return srcCommitAuditBody(newCommitFilesCommit(d, files, func(string, []byte) (bool, error) {
+					return false, nil
+				}))
+			},
+			id: "ws/repo/main",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/refs/branches/main":     `{"name": "main", "target": {"hash": "4f3b3c2a1d0e"}}`,
+				"/2.0/repositories/ws/repo/src/4f3b3c2a1d0e/a.txt": "a",
+			},
+			state: map[string]string{
+				"workspace":         "ws",
+				"repo_slug":         "repo",
+				"branch":            "main",
+				"file_sha256.%":     "1",
+				"file_sha256.a.txt": "previous",
+			},
+			ignored: map[string]string{
+				"workspace":         "names the endpoint",
+				"repo_slug":         "names the endpoint",
+				"delete_on_destroy": "only used on destroy",
+				"commit_sha":        "set from the response of the commit",
+			},
+		},
+		"bitbucket_deploy_key": {
+			config: map[string]interface{}{
+				"workspace":  "ws",
+				"repository": "repo",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandsshKey(d), nil
+			},
+			id: "ws/repo/1",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/deploy-keys/1": `{"id": 1, "key": "ssh-ed25519 AAAA", "label": "label", "comment": "comment"}`,
+			},
+		},
+		"bitbucket_deployment": {
+			config: map[string]interface{}{
+				"repository": "ws/repo",
+				"name":       "production",
+				"stage":      "Production",
+			},
+			samples: map[string][2]interface{}{
+				"restrictions": {
+					[]interface{}{map[string]interface{}{"admin_only": true}},
+					[]interface{}{map[string]interface{}{"admin_only": false}},
+				},
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return newDeploymentFromResource(d), nil
+			},
+			id: "ws/repo:env",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/environments/env": `{
+					"uuid": "env",
+					"name": "production",
+					"environment_type": {"name": "Production"},
+					"restrictions": {"admin_only": true}
+				}`,
+			},
+		},
+		"bitbucket_deployment_variable": {
+			config: map[string]interface{}{
+				"deployment": "ws/repo:env",
+				"key":        "KEY",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return newDeploymentVariableFromResource(d), nil
+			},
+			id: "var",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/deployments_config/environments/env/variables?pagelen=100": `{"size": 1, "values": [{"uuid": "var", "key": "KEY", "value": "value", "secured": true}]}`,
+			},
+			state: map[string]string{
+				"deployment": "ws/repo:env",
+			},
+			ignored: testPipelineVariableIgnored("deployment"),
+		},
+		"bitbucket_group": {
+			config: map[string]interface{}{
+				"workspace": "ws",
+				"name":      "developers",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandGroup(d), nil
+			},
+			id: "ws/developers",
+			api: listResourceTestTransport{
+				"/1.0/groups/ws/developers": `{
+					"name": "Developers",
+					"slug": "developers",
+					"auto_add": true,
+					"permission": "write",
+					"email_forwarding_disabled": true
+				}`,
+			},
+		},
+		"bitbucket_hook": {
+			config: map[string]interface{}{
+				"owner":       "ws",
+				"repository":  "repo",
+				"url":         "https://example.com/hook",
+				"description": "hook",
+				"events":      []interface{}{"repo:push"},
+			},
+			samples: map[string][2]interface{}{
+				"events": {
+					[]interface{}{"repo:push"},
+					[]interface{}{"repo:fork"},
+				},
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return createHook(d), nil
+			},
+			id: "hook",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/hooks/hook": `{
+					"uuid": "{hook}",
+					"url": "https://example.com/hook",
+					"description": "hook",
+					"active": true,
+					"skip_cert_verification": true,
+					"events": ["repo:push"]
+				}`,
+			},
+			state: map[string]string{
+				"owner":      "ws",
+				"repository": "repo",
+			},
+			ignored: map[string]string{
+				"owner":      "names the endpoint",
+				"repository": "names the endpoint",
+			},
+		},
+		"bitbucket_pipeline_schedule": {
+			config: map[string]interface{}{
+				"workspace":    "ws",
+				"repository":   "repo",
+				"enabled":      true,
+				"cron_pattern": "0 0 * * *",
+				"target":       testPipelineScheduleTargetSamples[0],
+			},
+			samples: map[string][2]interface{}{
+				"target": testPipelineScheduleTargetSamples,
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandCreatePipelineSchedule(d), nil
+			},
+			id: "ws/repo/schedule",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/pipelines_config/schedules/schedule": `{
+					"uuid": "schedule",
+					"enabled": true,
+					"cron_pattern": "0 0 * * *",
+					"target": {"ref_name": "main", "ref_type": "branch", "selector": {"type": "branches", "pattern": "main"}}
+				}`,
+			},
+		},
+		"bitbucket_pipeline_ssh_key": {
+			config: map[string]interface{}{
+				"workspace":  "ws",
+				"repository": "repo",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandPipelineSshKey(d), nil
+			},
+			id: "ws/repo",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/pipelines_config/ssh/key_pair": `{"public_key": "ssh-rsa AAAA"}`,
+			},
+		},
+		"bitbucket_pipeline_ssh_known_host": {
+			config: map[string]interface{}{
+				"workspace":  "ws",
+				"repository": "repo",
+				"public_key": testKnownHostKeySamples[0],
+			},
+			samples: map[string][2]interface{}{
+				"public_key": testKnownHostKeySamples,
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandPipelineSshKnownHost(d), nil
+			},
+			id: "ws/repo/host",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/pipelines_config/ssh/known_hosts/host": `{
+					"uuid": "host",
+					"hostname": "example.com",
+					"public_key": {"key_type": "ssh-ed25519", "key": "AAAA", "md5_fingerprint": "md5", "sha256_fingerprint": "sha256"}
+				}`,
+			},
+		},
+		"bitbucket_project": {
+			config: map[string]interface{}{
+				"owner": "ws",
+				"name":  "project",
+				"key":   "PROJ",
+			},
+			samples: map[string][2]interface{}{
+				"link": testLinkSamples,
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return newProjectFromResource(d)
+			},
+			id: "ws/PROJ",
+			api: listResourceTestTransport{
+				"/2.0/workspaces/ws/projects/PROJ": `{
+					"key": "PROJ",
+					"name": "project",
+					"uuid": "{project}",
+					"description": "description",
+					"is_private": true,
+					"has_publicly_visible_repos": true,
+					"links": {"avatar": {"href": "https://bitbucket.org/account/user/ws/projects/PROJ/avatar/32"}}
+				}`,
+			},
+			ignored: map[string]string{
+				"deletion_protection": "only guards deletion",
+				"on_destroy":          "only changes deletion",
+				"avatar_file":         "uploaded when avatar_sha256 changes",
+				"avatar_base64":       "uploaded when avatar_sha256 changes",
+				"avatar_sha256":       "hash of the last uploaded avatar",
+			},
+		},
+		"bitbucket_project_branching_model": {
+			config: map[string]interface{}{
+				"workspace":   "ws",
+				"project":     "PROJ",
+				"development": []interface{}{map[string]interface{}{"name": "develop"}},
+			},
+			samples: testBranchingModelSamples,
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandBranchingModel(d), nil
+			},
+			id: "ws/PROJ",
+			api: listResourceTestTransport{
+				"/2.0/workspaces/ws/projects/PROJ/branching-model": testBranchingModelAPI,
+			},
+		},
+		"bitbucket_repository_group_permission": {
+			config: map[string]interface{}{
+				"workspace":  "ws",
+				"repo_slug":  "repo",
+				"group_slug": "developers",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return createRepositoryGroupPermission(d), nil
+			},
+			id: "ws:repo:developers",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/permissions-config/groups/developers": `{"permission": "write", "group": {"slug": "developers", "workspace": {"slug": "ws"}}}`,
+			},
+		},
+		"bitbucket_repository_user_permission": {
+			config: map[string]interface{}{
+				"workspace": "ws",
+				"repo_slug": "repo",
+				"user_id":   "user",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return createRepositoryUserPermission(d), nil
+			},
+			id: "ws:repo:user",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/permissions-config/users/user": `{"permission": "write", "user": {"uuid": "user"}}`,
+			},
+		},
+		"bitbucket_repository_variable": {
+			config: map[string]interface{}{
+				"repository": "ws/repo",
+				"key":        "KEY",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return newRepositoryVariableFromResource(d), nil
+			},
+			id: "KEY",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/pipelines_config/variables/var": `{"uuid": "var", "key": "KEY", "value": "value", "secured": true}`,
+			},
+			state: map[string]string{
+				"repository": "ws/repo",
+				"uuid":       "var",
+			},
+			ignored: testPipelineVariableIgnored("repository", "uuid"),
+		},
+		"bitbucket_ssh_key": {
+			config: map[string]interface{}{
+				"user": "user",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return expandsshKey(d), nil
+			},
+			id: "user/key",
+			api: listResourceTestTransport{
+				"/2.0/users/user/ssh-keys/key": `{"uuid": "key", "key": "ssh-ed25519 AAAA", "label": "label", "comment": "comment"}`,
+			},
+		},
+		"bitbucket_tag": {
+			config: map[string]interface{}{
+				"workspace": "ws",
+				"repo_slug": "repo",
+				"name":      "v1.0.0",
+				"target":    "main",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				// The target stands in for the commit it resolves to.
+				return newTagFromResource(d, d.Get("target").(string)), nil
+			},
+			id: "ws/repo/v1.0.0",
+			api: listResourceTestTransport{
+				"/2.0/repositories/ws/repo/refs/tags/v1.0.0": `{
+					"name": "v1.0.0",
+					"message": "Release 1.0.0",
+					"target": {"hash": "4f3b3c2a1d0e"},
+					"tagger": {"raw": "Tagger <tagger@example.com>"},
+					"date": "2024-01-02T03:04:05+00:00"
+				}`,
+			},
+		},
+		"bitbucket_workspace_hook": {
+			config: map[string]interface{}{
+				"workspace":   "ws",
+				"url":         "https://example.com/hook",
+				"description": "hook",
+				"events":      []interface{}{"repo:push"},
+			},
+			samples: map[string][2]interface{}{
+				"events": {
+					[]interface{}{"repo:push"},
+					[]interface{}{"repo:fork"},
+				},
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return createHook(d), nil
+			},
+			id: "hook",
+			api: listResourceTestTransport{
+				"/2.0/workspaces/ws/hooks/hook": `{
+					"uuid": "{hook}",
+					"url": "https://example.com/hook",
+					"description": "hook",
+					"active": true,
+					"skip_cert_verification": true,
+					"events": ["repo:push"]
+				}`,
+			},
+			state: map[string]string{
+				"workspace": "ws",
+			},
+			ignored: map[string]string{
+				"workspace": "names the endpoint",
+			},
+		},
+		"bitbucket_workspace_variable": {
+			config: map[string]interface{}{
+				"workspace": "ws",
+				"key":       "KEY",
+			},
+			body: func(d *schema.ResourceData) (interface{}, error) {
+				return newWorkspaceVariableFromResource(d), nil
+			},
+			id: "ws/var",
+			api: listResourceTestTransport{
+				"/2.0/workspaces/ws/pipelines-config/variables/var": `{"uuid": "var", "key": "KEY", "value": "value", "secured": true}`,
+			},
+			ignored: testPipelineVariableIgnored(),
+		},
+	}
+
+	resources := Provider().ResourcesMap
+
+	names := make([]string, 0, len(resources))
+	for name := range resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for name := range audits {
+		if _, ok := resources[name]; !ok {
+			t.Errorf("audited resource %s is not in the provider", name)
+		}
+	}
+
+	for name := range schemaAuditExemptions {
+		if _, ok := resources[name]; !ok {
+			t.Errorf("exempted resource %s is not in the provider", name)
+		}
+		if _, ok := audits[name]; ok {
+			t.Errorf("resource %s is both audited and exempted", name)
+		}
+	}
+
+	for _, name := range names {
+		audit, ok := audits[name]
+		if !ok {
+			if _, exempted := schemaAuditExemptions[name]; !exempted {
+				t.Errorf("resource %s has neither a schema audit nor an exemption", name)
+			}
+			continue
+		}
+		audit.resource = resources[name]
+
+		t.Run(name, func(t *testing.T) {
+			read := audit.read(t)
+
+			attributes := make([]string, 0, len(audit.resource.Schema))
+			for attribute := range audit.resource.Schema {
+				attributes = append(attributes, attribute)
+			}
+			sort.Strings(attributes)
+
+			for _, attribute := range attributes {
+				_, ignored := audit.ignored[attribute]
+				sent := !ignored && audit.sent(t, attribute)
+
+				switch {
+				case ignored && read[attribute]:
+					t.Errorf("attribute %q is ignored, but is read", attribute)
+				case !ignored && !sent && !read[attribute]:
+					t.Errorf("attribute %q is neither sent to nor read from the API", attribute)
+				}
+			}
+
+			for attribute := range audit.ignored {
+				if _, ok := audit.resource.Schema[attribute]; !ok {
+					t.Errorf("ignored attribute %q is not in the schema", attribute)
+				}
+			}
+		})
+	}
+}
+
+// sent reports whether changing attribute changes the request body.
+func (a schemaAudit) sent(t *testing.T, attribute string) bool {
+	t.Helper()
+
+	s := a.resource.Schema[attribute]
+	if !s.Optional && !s.Required {
+		return false
+	}
+
+	values, ok := a.samples[attribute]
+	if !ok {
+		switch s.Type {
+		case schema.TypeString:
+			values = [2]interface{}{"audit-a", "audit-b"}
+		case schema.TypeBool:
+			values = [2]interface{}{true, false}
+		case schema.TypeInt:
+			values = [2]interface{}{1, 2}
+		default:
+			t.Errorf("no sample values for attribute %q", attribute)
+			return false
+		}
+	}
+
+	var bodies [2]string
+	for i, value := range values {
+		config := map[string]interface{}{}
+		for k, v := range a.config {
+			config[k] = v
+		}
+		config[attribute] = value
+
+		body, err := a.body(schema.TestResourceDataRaw(t, a.resource.Schema, config))
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		raw, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		bodies[i] = string(raw)
+	}
+
+	return bodies[0] != bodies[1]
+}
+
+// read returns the attributes Read sets from the API responses.
+func (a schemaAudit) read(t *testing.T) map[string]bool {
+	t.Helper()
+
+	clients, err := newClients(providerSettings{
+		Username:   "user",
+		Password:   "password",
+		HTTPClient: &http.Client{Transport: a.api},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	d := a.resource.Data(&terraform.InstanceState{ID: a.id, Attributes: a.state})
+
+	readFunc := a.resource.ReadWithoutTimeout
+	if readFunc == nil {
+		readFunc = a.resource.ReadContext
+	}
+	if diags := readFunc(context.Background(), d, clients); diags.HasError() {
+		t.Fatalf("unexpected error reading %s: %v", a.id, diags)
+	}
+
+	// Attributes only carried over from the state are not read.
+	read := map[string]bool{}
+	for attribute := range a.resource.Schema {
+		v, ok := d.GetOk(attribute)
+		_, inState := a.state[attribute]
+		read[attribute] = ok && !reflect.ValueOf(v).IsZero() && !inState
+	}
+
+	return read
+}
@@ -4,11 +4,15 @@ import (
 	"bytes"
 	crand "crypto/rand"
 	"crypto/rsa"
+	"crypto/subtle"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/pem"
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -64,3 +68,66 @@ func pemEncode(b []byte, block string) (string, error) {
 
 	return buf.String(), nil
 }
+
+const secretHashAlgorithm = "argon2id"
+
+// hashSecret returns a randomly salted argon2id hash of value, encoded as
+// `argon2id$<salt>$<key>` so it can be kept in state in place of the secret.
+func hashSecret(value string) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := crand.Read(salt); err != nil {
+		return "", err
+	}
+
+	return encodeSecretHash(value, salt), nil
+}
+
+// secretMatchesHash reports whether value produces the given encoded hash.
+func secretMatchesHash(value, encoded string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 3 || parts[0] != secretHashAlgorithm {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(encodeSecretHash(value, salt)), []byte(encoded)) == 1
+}
+
+func encodeSecretHash(value string, salt []byte) string {
+	key := argon2.IDKey([]byte(value), salt, 2, 19*1024, 1, 32)
+	return fmt.Sprintf("%s$%s$%s", secretHashAlgorithm,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key))
+}
+
+// dataSourceSchemaFromResourceSchema returns a copy of a resource schema where
+// every attribute is computed, for data sources that read the same object.
+func dataSourceSchemaFromResourceSchema(rs map[string]*schema.Schema) map[string]*schema.Schema {
+	ds := make(map[string]*schema.Schema, len(rs))
+
+	for k, v := range rs {
+		dv := &schema.Schema{
+			Type:        v.Type,
+			Description: v.Description,
+			Sensitive:   v.Sensitive,
+			Computed:    true,
+		}
+
+		switch elem := v.Elem.(type) {
+		case *schema.Resource:
+			dv.Elem = &schema.Resource{
+				Schema: dataSourceSchemaFromResourceSchema(elem.Schema),
+			}
+		case *schema.Schema:
+			dv.Elem = elem
+		}
+
+		ds[k] = dv
+	}
+
+	return ds
+}